@@ -8,12 +8,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/yinziyang/cybertron/pkg/server"
-	"github.com/yinziyang/cybertron/pkg/tasks"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/server"
+	"github.com/yinziyang/cybertron/pkg/tasks"
 )
 
 // TaskType is the task type.
@@ -50,11 +53,88 @@ func ParseTaskType(s string) (TaskType, error) {
 	return "", fmt.Errorf("invalid task type value %#v", s)
 }
 
+// modelSpec describes a named model served in multi-model mode.
+type modelSpec struct {
+	name  string
+	task  TaskType
+	model string
+}
+
+// parseModelSpecs parses a comma-separated list of models in the form
+// "name=task:model", e.g. "ner=token-classification:dbmdz/bert-large-cased-finetuned-conll03-english".
+func parseModelSpecs(s string) ([]modelSpec, error) {
+	var specs []modelSpec
+	for _, item := range strings.Split(s, ",") {
+		name, rest, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid model spec %#v: expected \"name=task:model\"", item)
+		}
+		task, model, ok := strings.Cut(rest, ":")
+		if !ok || name == "" || model == "" {
+			return nil, fmt.Errorf("invalid model spec %#v: expected \"name=task:model\"", item)
+		}
+		taskType, err := ParseTaskType(task)
+		if err != nil {
+			return nil, fmt.Errorf("invalid model spec %#v: %w", item, err)
+		}
+		specs = append(specs, modelSpec{name: name, task: taskType, model: model})
+	}
+	return specs, nil
+}
+
+// parseAPIKeys parses a comma-separated list of API keys, each optionally
+// prefixed by the name of its client as "name=key".
+func parseAPIKeys(s string) ([]server.APIKey, error) {
+	var keys []server.APIKey
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		name, key, ok := strings.Cut(item, "=")
+		if !ok {
+			name, key = "", item
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid API key %#v: expected \"key\" or \"name=key\"", name)
+		}
+		keys = append(keys, server.APIKey{Name: name, Key: key})
+	}
+	return keys, nil
+}
+
 // config represents the configuration of the server.
 type config struct {
 	task         TaskType
 	loaderConfig *tasks.Config
 	serverConfig *server.Config
+	// models are the named models to serve in multi-model mode.
+	// If set, task and loaderConfig.ModelName are ignored.
+	models []modelSpec
+	// adminEnabled enables the model management API.
+	adminEnabled bool
+	// jobsEnabled enables the jobs API, running the task calls
+	// asynchronously as configured by jobsConfig.
+	jobsEnabled bool
+	jobsConfig  server.JobsConfig
+	// tracingExporter enables tracing, exporting the spans with the given
+	// exporter ("otlp"|"stdout").
+	tracingExporter string
+	// tracingEndpoint is the OTLP endpoint receiving the spans.
+	tracingEndpoint string
+	// apiKeys are the API keys set from the environment, in addition to the
+	// ones of apiKeysFile.
+	apiKeys     []server.APIKey
+	apiKeysFile string
+	// adminKeys are the names of the API keys granted access to the model
+	// management API, in addition to the admin keys of apiKeysFile.
+	adminKeys []string
+	// keyLimits are the default limits of the API keys without their own.
+	keyLimits server.APIKey
+	// warmupInput is the input of the inference run on each model once
+	// loaded, before serving it. No warmup if empty.
+	warmupInput string
+	// configFile is the YAML or JSON file setting config values (see loadFile).
+	configFile string
+	// printConfig makes the server print the resolved configuration and exit.
+	printConfig bool
 }
 
 // loadEnv loads config values from environment variables.
@@ -83,6 +163,46 @@ func (conf *config) loadEnv() error {
 	if err := lookupEnvAndParse("MODEL_TASK", ParseTaskType, &conf.task); err != nil {
 		return err
 	}
+	if err := lookupEnvAndParse("MODELS", parseModelSpecs, &conf.models); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("ADMIN_ENABLED", parseBool, &conf.adminEnabled); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("JOBS_ENABLED", parseBool, &conf.jobsEnabled); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("JOB_WORKERS", strconv.Atoi, &conf.jobsConfig.Workers); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("JOB_QUEUE_LENGTH", strconv.Atoi, &conf.jobsConfig.MaxQueueLength); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("JOB_TTL", time.ParseDuration, &conf.jobsConfig.TTL); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("JOB_CALLBACK_HOSTS", parseCommaSplit, &conf.jobsConfig.CallbackHosts); err != nil {
+		return err
+	}
+	lookupEnv("TRACING_EXPORTER", &conf.tracingExporter)
+	lookupEnv("TRACING_ENDPOINT", &conf.tracingEndpoint)
+	if err := lookupEnvAndParse("API_KEYS", parseAPIKeys, &conf.apiKeys); err != nil {
+		return err
+	}
+	lookupEnv("API_KEYS_FILE", &conf.apiKeysFile)
+	if err := lookupEnvAndParse("ADMIN_KEYS", parseCommaSplit, &conf.adminKeys); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("RATE_LIMIT", parseFloat, &conf.keyLimits.RateLimit); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("RATE_BURST", strconv.Atoi, &conf.keyLimits.RateBurst); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("TOKEN_QUOTA", parseInt64, &conf.keyLimits.TokenQuota); err != nil {
+		return err
+	}
+	lookupEnv("WARMUP_INPUT", &conf.warmupInput)
 
 	s := conf.serverConfig
 	lookupEnv("NETWORK", &s.Network)
@@ -95,6 +215,74 @@ func (conf *config) loadEnv() error {
 	}
 	lookupEnv("TLS_CERT", &s.TLSCert)
 	lookupEnv("TLS_KEY", &s.TLSKey)
+	lookupEnv("TLS_CLIENT_CA", &s.TLSClientCA)
+	if err := lookupEnvAndParse("TLS_CLIENT_SUBJECTS", parseCommaSplit, &s.TLSClientSubjects); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_BATCH_SIZE", strconv.Atoi, &s.MaxBatchSize); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_BATCH_WAIT", time.ParseDuration, &s.MaxBatchWait); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("CACHE_SIZE", strconv.Atoi, &s.CacheSize); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("CACHE_TTL", time.ParseDuration, &s.CacheTTL); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("METRICS_ENABLED", parseBool, &s.MetricsEnabled); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("ACCESS_LOG_ENABLED", parseBool, &s.AccessLogEnabled); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("PAYLOAD_LOG_SAMPLE_RATE", parseFloat, &s.PayloadLogSampleRate); err != nil {
+		return err
+	}
+	lookupEnv("CAPTURE_DIR", &s.CaptureDir)
+	if err := lookupEnvAndParse("CAPTURE_SAMPLE_RATE", parseFloat, &s.CaptureSampleRate); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("CAPTURE_MAX_FILE_SIZE", parseInt64, &s.CaptureMaxFileSize); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("CAPTURE_MAX_FILES", strconv.Atoi, &s.CaptureMaxFiles); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("REFLECTION_ENABLED", parseBool, &s.ReflectionEnabled); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_CONCURRENT_INFERENCES", strconv.Atoi, &s.MaxConcurrentInferences); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_QUEUE_LENGTH", strconv.Atoi, &s.MaxQueueLength); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_QUEUE_TIME", time.ParseDuration, &s.MaxQueueTime); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("PRIORITY_AGING_TIME", time.ParseDuration, &s.PriorityAgingTime); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("REQUEST_TIMEOUT", time.ParseDuration, &s.RequestTimeout); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_INPUT_CHARS", strconv.Atoi, &s.MaxInputChars); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_INPUT_TOKENS", strconv.Atoi, &s.MaxInputTokens); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_CANDIDATE_LABELS", strconv.Atoi, &s.MaxCandidateLabels); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("MAX_BATCH_INPUTS", strconv.Atoi, &s.MaxBatchInputs); err != nil {
+		return err
+	}
+	if err := lookupEnvAndParse("QUOTA_PERIOD", time.ParseDuration, &s.QuotaPeriod); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -105,6 +293,8 @@ func (conf *config) loadEnv() error {
 // The flags are defined using FlagSet.Func, so that if a command line flag is
 // not encountered, its related config value is not overridden with any default.
 func (conf *config) bindFlagSet(fs *flag.FlagSet) {
+	fs.Func("config", `YAML or JSON file setting the options, overridden by the env vars and the flags`, flagAssignFunc(&conf.configFile))
+	fs.BoolVar(&conf.printConfig, "print-config", false, `print the resolved configuration, with the secrets redacted, and exit`)
 	fs.Func("loglevel", "zerolog global level", func(v string) error {
 		l, err := zerolog.ParseLevel(v)
 		if err != nil {
@@ -126,6 +316,34 @@ func (conf *config) bindFlagSet(fs *flag.FlagSet) {
 		flagParseFunc(tasks.ParseFloatPrecision, &mm.ConversionPrecision))
 	fs.Func("task", `type of inference/computation that the model can fulfill ("text-generation"|"zero-shot-classification"|"question-answering"|"text-classification"|"token-classification"|"text-encoding"|"language-modeling")`,
 		flagParseFunc(ParseTaskType, &conf.task))
+	fs.Func("models", `models to serve together, as comma separated "name=task:model" (overrides -model and -task)`,
+		flagParseFunc(parseModelSpecs, &conf.models))
+	fs.Func("admin", `whether to enable the model management API ("true"|"false")`,
+		flagParseFunc(parseBool, &conf.adminEnabled))
+	fs.Func("jobs", `whether to enable the jobs API, running the task calls asynchronously ("true"|"false")`,
+		flagParseFunc(parseBool, &conf.jobsEnabled))
+	fs.Func("job-workers", `maximum number of jobs running at the same time (default 1)`,
+		flagParseFunc(strconv.Atoi, &conf.jobsConfig.Workers))
+	fs.Func("job-queue-length", `maximum number of jobs waiting for a worker (default 100)`,
+		flagParseFunc(strconv.Atoi, &conf.jobsConfig.MaxQueueLength))
+	fs.Func("job-ttl", `time the finished jobs and their results are kept (e.g. "30m", default "1h")`,
+		flagParseFunc(time.ParseDuration, &conf.jobsConfig.TTL))
+	fs.Func("job-callback-hosts", `hosts to which the jobs can be delivered by callback URL (comma separated, empty disables callbacks)`,
+		flagParseFunc(parseCommaSplit, &conf.jobsConfig.CallbackHosts))
+	fs.Func("tracing-exporter", `enables tracing, exporting the spans ("otlp"|"stdout")`, flagAssignFunc(&conf.tracingExporter))
+	fs.Func("tracing-endpoint", `OTLP endpoint receiving the spans, as "host:port" (default from OTEL_EXPORTER_OTLP_ENDPOINT)`, flagAssignFunc(&conf.tracingEndpoint))
+	fs.Func("api-keys-file", `JSON file of the API keys enabling authentication, as [{"name", "key", "rate_limit", "rate_burst", "token_quota", "weight", "admin"}]`,
+		flagAssignFunc(&conf.apiKeysFile))
+	fs.Func("admin-keys", `names of the API keys allowed to use the model management API (comma separated)`,
+		flagParseFunc(parseCommaSplit, &conf.adminKeys))
+	fs.Func("rate-limit", `default maximum requests per second of each API key (0 for no limit)`,
+		flagParseFunc(parseFloat, &conf.keyLimits.RateLimit))
+	fs.Func("rate-burst", `default maximum burst of requests of each API key (default the rate limit)`,
+		flagParseFunc(strconv.Atoi, &conf.keyLimits.RateBurst))
+	fs.Func("token-quota", `default maximum input tokens of each API key per quota period (0 for no limit)`,
+		flagParseFunc(parseInt64, &conf.keyLimits.TokenQuota))
+	fs.Func("warmup-input", `input of the inference run on each model before serving it (empty disables the warmup)`,
+		flagAssignFunc(&conf.warmupInput))
 
 	s := conf.serverConfig
 	fs.Func("network", "network type for server listening", flagAssignFunc(&s.Network))
@@ -136,6 +354,81 @@ func (conf *config) bindFlagSet(fs *flag.FlagSet) {
 		flagParseFunc(parseBool, &s.TLSEnabled))
 	fs.Func("tls-cert", "TLS cert filename", flagAssignFunc(&s.TLSCert))
 	fs.Func("tls-key", "TLS key filename", flagAssignFunc(&s.TLSKey))
+	fs.Func("tls-client-ca", "CA bundle filename verifying the client certificates (enables mutual TLS)", flagAssignFunc(&s.TLSClientCA))
+	fs.Func("tls-client-subjects", `client certificate subjects allowed to call the server (comma separated)`,
+		flagParseFunc(parseCommaSplit, &s.TLSClientSubjects))
+	fs.Func("max-batch-size", `maximum number of concurrent requests run as one batch (0 or 1 disables batching)`,
+		flagParseFunc(strconv.Atoi, &s.MaxBatchSize))
+	fs.Func("max-batch-wait", `maximum time a request waits for others to join its batch (e.g. "5ms")`,
+		flagParseFunc(time.ParseDuration, &s.MaxBatchWait))
+	fs.Func("cache-size", `maximum number of responses cached for each model (0 disables caching)`,
+		flagParseFunc(strconv.Atoi, &s.CacheSize))
+	fs.Func("cache-ttl", `time after which the cached responses expire (e.g. "10m", 0 for never)`,
+		flagParseFunc(time.ParseDuration, &s.CacheTTL))
+	fs.Func("metrics", `whether to serve Prometheus metrics on /metrics ("true"|"false", default "true")`,
+		flagParseFunc(parseBool, &s.MetricsEnabled))
+	fs.Func("access-log", `whether to log each request served ("true"|"false", default "true")`,
+		flagParseFunc(parseBool, &s.AccessLogEnabled))
+	fs.Func("payload-log-sample-rate", `fraction of the requests logged with their redacted payloads, between 0 and 1 (0 disables payload logging)`,
+		flagParseFunc(parseFloat, &s.PayloadLogSampleRate))
+	fs.Func("capture-dir", `directory where a sample of the requests and responses is captured to JSON Lines files (empty disables capture)`,
+		flagAssignFunc(&s.CaptureDir))
+	fs.Func("capture-sample-rate", `fraction of the requests captured, between 0 and 1 (default 1)`,
+		flagParseFunc(parseFloat, &s.CaptureSampleRate))
+	fs.Func("capture-max-file-size", `size in bytes after which a capture file is rotated (default 67108864)`,
+		flagParseFunc(parseInt64, &s.CaptureMaxFileSize))
+	fs.Func("capture-max-files", `number of capture files kept, the oldest ones being deleted (default 10)`,
+		flagParseFunc(strconv.Atoi, &s.CaptureMaxFiles))
+	fs.Func("reflection", `whether to enable the gRPC server reflection service ("true"|"false")`,
+		flagParseFunc(parseBool, &s.ReflectionEnabled))
+	fs.Func("max-concurrent-inferences", `maximum number of requests served at the same time (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxConcurrentInferences))
+	fs.Func("max-queue-length", `maximum number of requests waiting for their turn (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxQueueLength))
+	fs.Func("max-queue-time", `maximum time a request waits for its turn (e.g. "2s", 0 for no limit)`,
+		flagParseFunc(time.ParseDuration, &s.MaxQueueTime))
+	fs.Func("priority-aging-time", `time after which a waiting request is served before the ones of higher priority (e.g. "5s", default "10s", negative for strict priority)`,
+		flagParseFunc(time.ParseDuration, &s.PriorityAgingTime))
+	fs.Func("request-timeout", `maximum time to serve a request (e.g. "30s", 0 for no limit)`,
+		flagParseFunc(time.ParseDuration, &s.RequestTimeout))
+	fs.Func("max-input-chars", `maximum number of characters of each input text (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxInputChars))
+	fs.Func("max-input-tokens", `maximum number of tokens of each input sequence, below the model limit (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxInputTokens))
+	fs.Func("max-candidate-labels", `maximum number of candidate labels of zero-shot classification requests (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxCandidateLabels))
+	fs.Func("max-batch-inputs", `maximum number of inputs of batch requests (0 for no limit)`,
+		flagParseFunc(strconv.Atoi, &s.MaxBatchInputs))
+	fs.Func("quota-period", `period of the token quotas of the API keys (e.g. "1h", default "24h")`,
+		flagParseFunc(time.ParseDuration, &s.QuotaPeriod))
+}
+
+// resolveAPIKeys sets the API keys of the server configuration, loading the
+// ones of apiKeysFile, granting the admin scope to the ones of adminKeys, and
+// applying the default limits to the keys without their own.
+func (conf *config) resolveAPIKeys() error {
+	keys := conf.apiKeys
+	if conf.apiKeysFile != "" {
+		fileKeys, err := server.LoadAPIKeys(conf.apiKeysFile)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, fileKeys...)
+	}
+	for i := range keys {
+		k := &keys[i]
+		if k.Name != "" && slices.Contains(conf.adminKeys, k.Name) {
+			k.Admin = true
+		}
+		if k.RateLimit == 0 {
+			k.RateLimit, k.RateBurst = conf.keyLimits.RateLimit, conf.keyLimits.RateBurst
+		}
+		if k.TokenQuota == 0 {
+			k.TokenQuota = conf.keyLimits.TokenQuota
+		}
+	}
+	conf.serverConfig.APIKeys = keys
+	return nil
 }
 
 // lookupEnv looks up the value of the given environment variable and assign it to dest.
@@ -179,9 +472,24 @@ func flagParseFunc[T any](parse func(string) (T, error), dest *T) func(string) e
 	}
 }
 
-// parseCommaSplit parses the given string as a comma-separated list of strings.
+// parseCommaSplit parses the given string as a comma-separated list of
+// strings, trimming the spaces around them.
 func parseCommaSplit(s string) ([]string, error) {
-	return strings.Split(s, ","), nil
+	items := strings.Split(s, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items, nil
+}
+
+// parseFloat parses the given string as a 64-bit float.
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// parseInt64 parses the given string as a 64-bit integer.
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
 }
 
 // parseBool parses the given string as a boolean.
@@ -0,0 +1,279 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the secrets in the printed configuration.
+const redacted = "[redacted]"
+
+// configFilename returns the name of the config file, set by the -config
+// flag or by the CYBERTRON_CONFIG env var. It is looked up before the config
+// values, which the config file is loaded under.
+func configFilename(args []string) string {
+	conf := newConfig()
+	lookupEnv("CONFIG", &conf.configFile)
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	conf.bindFlagSet(fs)
+	// the errors are reported once the flags are parsed for good
+	_ = fs.Parse(args)
+	return conf.configFile
+}
+
+// isCommandOption reports whether the flag with the given name sets how the
+// server is run, rather than a config value.
+func isCommandOption(name string) bool {
+	return name == "config" || name == "print-config"
+}
+
+// fileOptions returns the options which can be set by the config file (and
+// by the env vars) but not by the flags, such as the secrets.
+func (conf *config) fileOptions() map[string]func(string) error {
+	return map[string]func(string) error{
+		"api-keys": flagParseFunc(parseAPIKeys, &conf.apiKeys),
+	}
+}
+
+// loadFile loads config values from the given YAML or JSON file: a mapping
+// of the names of the flags to their values, e.g.
+//
+//	address: 0.0.0.0:8080
+//	models:
+//	  - ner=token-classification:dbmdz/bert-large-cased-finetuned-conll03-english
+//	  - sentiment=text-classification:distilbert-base-uncased-finetuned-sst-2-english
+//	max-queue-time: 2s
+//
+// The values are parsed as the ones of the flags, a list being the same as
+// its items separated by commas. A null value leaves the option unset. The
+// API keys can be set by "api-keys", as the CYBERTRON_API_KEYS env var.
+func (conf *config) loadFile(fs *flag.FlagSet, filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	// JSON being a subset of YAML, both are decoded as YAML
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil // empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid config file %s: line %d: expected a mapping of options to values", filename, root.Line)
+	}
+
+	fileOptions := conf.fileOptions()
+	for i := 0; i < len(root.Content); i += 2 {
+		key, node := root.Content[i], root.Content[i+1]
+		name := key.Value
+		set, ok := fileOptions[name]
+		if !ok {
+			if fs.Lookup(name) == nil || isCommandOption(name) {
+				return fmt.Errorf("invalid config file %s: line %d: unknown option %q", filename, key.Line, name)
+			}
+			set = func(v string) error { return fs.Set(name, v) }
+		}
+		value, ok, err := nodeValue(node)
+		if err != nil {
+			return fmt.Errorf("invalid config file %s: line %d: option %q: %w", filename, node.Line, name, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := set(value); err != nil {
+			return fmt.Errorf("invalid config file %s: line %d: invalid value for option %q: %w", filename, node.Line, name, err)
+		}
+	}
+	return nil
+}
+
+// nodeValue returns the value of a config file option as the one of a flag,
+// reporting whether it is set.
+func nodeValue(node *yaml.Node) (string, bool, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", false, nil
+		}
+		return node.Value, true, nil
+	case yaml.SequenceNode:
+		items := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", false, errors.New("expected a list of values")
+			}
+			items[i] = item.Value
+		}
+		return strings.Join(items, ","), len(items) > 0, nil
+	default:
+		return "", false, errors.New("expected a value or a list of values")
+	}
+}
+
+// validate checks the consistency of the resolved config values, whatever
+// their source.
+func (conf *config) validate() error {
+	s := conf.serverConfig
+	for _, rate := range []struct {
+		name  string
+		value float64
+	}{
+		{"payload-log-sample-rate", s.PayloadLogSampleRate},
+		{"capture-sample-rate", s.CaptureSampleRate},
+	} {
+		if rate.value < 0 || rate.value > 1 {
+			return fmt.Errorf("invalid %s %g: expected a value between 0 and 1", rate.name, rate.value)
+		}
+	}
+	if s.TLSEnabled && (s.TLSCert == "" || s.TLSKey == "") {
+		return errors.New("tls requires tls-cert and tls-key")
+	}
+	names := make(map[string]bool, len(conf.models))
+	for _, spec := range conf.models {
+		if names[spec.name] {
+			return fmt.Errorf("invalid models: duplicate model name %q", spec.name)
+		}
+		names[spec.name] = true
+	}
+	return nil
+}
+
+// print writes the resolved config values to w, in the format of the config
+// file, with the secrets redacted.
+func (conf *config) print(w io.Writer, fs *flag.FlagSet) error {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if !isCommandOption(f.Name) {
+			names = append(names, f.Name)
+		}
+	})
+	for name := range conf.fileOptions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := conf.values()
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range names {
+		v, ok := values[name]
+		if !ok {
+			return fmt.Errorf("missing value of option %q", name)
+		}
+		value := &yaml.Node{}
+		if err := value.Encode(v); err != nil {
+			return err
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// values returns the config values by option name, as printed. The unset
+// values which could not be parsed back, such as empty lists, are nil.
+func (conf *config) values() map[string]any {
+	mm, s := conf.loaderConfig, conf.serverConfig
+	var task any
+	if conf.task != "" {
+		task = string(conf.task)
+	}
+	var models []string
+	for _, spec := range conf.models {
+		models = append(models, fmt.Sprintf("%s=%s:%s", spec.name, spec.task, spec.model))
+	}
+	var apiKeys []string
+	for _, k := range conf.apiKeys {
+		if k.Name == "" {
+			apiKeys = append(apiKeys, redacted)
+			continue
+		}
+		apiKeys = append(apiKeys, k.Name+"="+redacted)
+	}
+	return map[string]any{
+		"loglevel":                   zerolog.GlobalLevel().String(),
+		"models-dir":                 mm.ModelsDir,
+		"model":                      mm.ModelName,
+		"hub-access-token":           redact(mm.HubAccessToken),
+		"model-download":             mm.DownloadPolicy.String(),
+		"model-conversion":           mm.ConversionPolicy.String(),
+		"model-conversion-precision": mm.ConversionPrecision.String(),
+		"task":                       task,
+		"models":                     models,
+		"admin":                      conf.adminEnabled,
+		"jobs":                       conf.jobsEnabled,
+		"job-workers":                conf.jobsConfig.Workers,
+		"job-queue-length":           conf.jobsConfig.MaxQueueLength,
+		"job-ttl":                    conf.jobsConfig.TTL.String(),
+		"job-callback-hosts":         conf.jobsConfig.CallbackHosts,
+		"tracing-exporter":           conf.tracingExporter,
+		"tracing-endpoint":           conf.tracingEndpoint,
+		"api-keys":                   apiKeys,
+		"api-keys-file":              conf.apiKeysFile,
+		"admin-keys":                 conf.adminKeys,
+		"rate-limit":                 conf.keyLimits.RateLimit,
+		"rate-burst":                 conf.keyLimits.RateBurst,
+		"token-quota":                conf.keyLimits.TokenQuota,
+		"warmup-input":               conf.warmupInput,
+		"network":                    s.Network,
+		"address":                    s.Address,
+		"allowed-origins":            s.AllowedOrigins,
+		"tls":                        s.TLSEnabled,
+		"tls-cert":                   s.TLSCert,
+		"tls-key":                    s.TLSKey,
+		"tls-client-ca":              s.TLSClientCA,
+		"tls-client-subjects":        s.TLSClientSubjects,
+		"max-batch-size":             s.MaxBatchSize,
+		"max-batch-wait":             s.MaxBatchWait.String(),
+		"cache-size":                 s.CacheSize,
+		"cache-ttl":                  s.CacheTTL.String(),
+		"metrics":                    s.MetricsEnabled,
+		"access-log":                 s.AccessLogEnabled,
+		"payload-log-sample-rate":    s.PayloadLogSampleRate,
+		"capture-dir":                s.CaptureDir,
+		"capture-sample-rate":        s.CaptureSampleRate,
+		"capture-max-file-size":      s.CaptureMaxFileSize,
+		"capture-max-files":          s.CaptureMaxFiles,
+		"reflection":                 s.ReflectionEnabled,
+		"max-concurrent-inferences":  s.MaxConcurrentInferences,
+		"max-queue-length":           s.MaxQueueLength,
+		"max-queue-time":             s.MaxQueueTime.String(),
+		"priority-aging-time":        s.PriorityAgingTime.String(),
+		"request-timeout":            s.RequestTimeout.String(),
+		"max-input-chars":            s.MaxInputChars,
+		"max-input-tokens":           s.MaxInputTokens,
+		"max-candidate-labels":       s.MaxCandidateLabels,
+		"max-batch-inputs":           s.MaxBatchInputs,
+		"quota-period":               s.QuotaPeriod.String(),
+	}
+}
+
+// redact returns the secret redacted, if set.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
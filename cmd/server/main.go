@@ -14,6 +14,11 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
 	"github.com/yinziyang/cybertron/pkg/server"
 	"github.com/yinziyang/cybertron/pkg/tasks"
 	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
@@ -23,15 +28,11 @@ import (
 	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
 	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
 	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
-	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 const defaultModelsDir = "models"
 const addrRandomPort = ":0"
+const defaultWarmupInput = "Hello, world!"
 
 // main is the entry point of the application.
 func main() {
@@ -46,20 +47,22 @@ func run() error {
 	initLogger()
 	loadDotenv()
 
-	conf := &config{
-		loaderConfig: &tasks.Config{ModelsDir: defaultModelsDir},
-		serverConfig: &server.Config{Address: addrRandomPort},
-	}
+	conf := newConfig()
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	conf.bindFlagSet(fs)
 
-	// load env vars values *before* parsing command line flags:
-	// this gives to the flag a priority over values from the environment.
+	// load the config file values, then the env vars values, *before*
+	// parsing command line flags: this gives to the flag a priority over
+	// values from the environment, and to both over the config file.
+	if filename := configFilename(os.Args[1:]); filename != "" {
+		if err := conf.loadFile(fs, filename); err != nil {
+			return err
+		}
+	}
 	if err := conf.loadEnv(); err != nil {
 		return err
 	}
 
-	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
-	conf.bindFlagSet(fs)
-
 	err := fs.Parse(os.Args[1:])
 	if errors.Is(err, flag.ErrHelp) {
 		return nil
@@ -67,32 +70,76 @@ func run() error {
 	if err != nil {
 		return err
 	}
-
-	m, err := loadModelForTask(conf)
-	if err != nil {
+	if err := conf.validate(); err != nil {
+		return err
+	}
+	if conf.printConfig {
+		return conf.print(os.Stdout, fs)
+	}
+	if err := conf.resolveAPIKeys(); err != nil {
 		return err
 	}
 
-	logMetrics()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
 
-	requestHandler, err := server.ResolveRequestHandler(m)
+	if conf.tracingExporter != "" {
+		tp, err := server.NewTracerProvider(ctx, conf.tracingExporter, conf.tracingEndpoint)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := tp.Shutdown(context.Background()); err != nil {
+				log.Err(err).Msg("failed to shut down tracer provider")
+			}
+		}()
+		conf.serverConfig.TracerProvider = tp
+	}
+
+	// the server starts listening, and reporting its health, while the
+	// models are loaded in the background
+	registry := server.NewRegistry()
+	conf.serverConfig.StartNotReady = true
+	handler, err := requestHandler(conf, registry)
 	if err != nil {
 		return err
 	}
+	s := server.New(conf.serverConfig, handler)
 
-	s := server.New(conf.serverConfig, requestHandler)
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		if err := loadModels(conf, registry); err != nil {
+			cancel(err)
+			return
+		}
+		logMetrics()
+		s.SetReady()
+		log.Info().Msg("server ready")
+	}()
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
-	defer stop()
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+	if err := context.Cause(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
 
-	return s.Start(ctx)
+// newConfig returns the default configuration.
+func newConfig() *config {
+	return &config{
+		loaderConfig: &tasks.Config{ModelsDir: defaultModelsDir},
+		serverConfig: &server.Config{Address: addrRandomPort, MetricsEnabled: true, AccessLogEnabled: true},
+		warmupInput:  defaultWarmupInput,
+	}
 }
 
+// logMetrics logs the CPU and RAM usage, once the models are loaded. It only
+// emits events, the logger being configured once by initLogger, as it runs
+// while the server is already serving requests.
 func logMetrics() {
-	// Set up zerolog to print with human-readable timestamps
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-
 	// Get total CPU count
 	totalCpu, _ := cpu.Counts(false)
 	// Get process CPU percentage
@@ -115,6 +162,84 @@ func logMetrics() {
 		Msg("RAM Metrics")
 }
 
+// modelSpecs returns the models to serve: the ones of multi-model mode, or
+// the single configured model named after itself.
+func modelSpecs(conf *config) []modelSpec {
+	if len(conf.models) > 0 {
+		return conf.models
+	}
+	mm := conf.loaderConfig.ModelName
+	return []modelSpec{{name: mm, task: conf.task, model: mm}}
+}
+
+// requestHandler returns the request handler serving the models of the
+// registry, along with the model management and jobs APIs if enabled.
+func requestHandler(conf *config, registry *server.Registry) (server.RequestHandler, error) {
+	handlers := []server.RequestHandler{registry}
+	if conf.adminEnabled {
+		admin := server.NewServerForModelAdmin(registry, func(ctx context.Context, task, model string) (any, error) {
+			taskType, err := ParseTaskType(task)
+			if err != nil {
+				return nil, err
+			}
+			return loadModel(ctx, conf, taskType, model)
+		})
+		handlers = append(handlers, admin)
+	}
+	if conf.jobsEnabled {
+		jobs, err := server.NewServerForJobs(registry, conf.jobsConfig)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, jobs)
+	}
+	if len(handlers) == 1 {
+		return registry, nil
+	}
+	return server.JoinRequestHandlers(handlers...), nil
+}
+
+// loadModels loads the configured model(s), adding them to the registry as
+// soon as they are ready.
+func loadModels(conf *config, registry *server.Registry) error {
+	for _, spec := range modelSpecs(conf) {
+		m, err := loadModel(context.Background(), conf, spec.task, spec.model)
+		if err != nil {
+			return fmt.Errorf("failed to load model %q: %w", spec.name, err)
+		}
+		if err := registry.AddVersion(spec.name, spec.model, m); err != nil {
+			return err
+		}
+		log.Info().Str("name", spec.name).Str("task", string(spec.task)).Str("model", spec.model).Msg("model loaded")
+	}
+	return nil
+}
+
+// loadModel loads the given model for the task, sharing the rest of the
+// loader configuration, warms it up and sets up dynamic batching and caching.
+func loadModel(ctx context.Context, conf *config, task TaskType, model string) (any, error) {
+	loaderConfig := *conf.loaderConfig
+	loaderConfig.ModelName = model
+	m, err := loadModelForTask(&config{task: task, loaderConfig: &loaderConfig})
+	if err != nil {
+		return nil, err
+	}
+	if conf.warmupInput != "" {
+		start := time.Now()
+		if err := server.Warmup(ctx, m, conf.warmupInput); err != nil {
+			return nil, err
+		}
+		log.Info().Str("model", model).Dur("duration", time.Since(start)).Msg("model warmed up")
+	}
+	return withServing(m, conf.serverConfig), nil
+}
+
+// withServing wraps the model with dynamic batching and response caching,
+// as configured.
+func withServing(m any, conf *server.Config) any {
+	return server.WithResponseCache(server.WithDynamicBatching(m, conf), conf)
+}
+
 func loadModelForTask(conf *config) (m any, err error) {
 	switch conf.task {
 	case ZeroShotClassificationTask:
@@ -0,0 +1,52 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+)
+
+// BatchResult is the result of an input of a batch request: either the
+// response or the error reported by the server for that input alone.
+type BatchResult[T any] struct {
+	Response T
+	// Err is a gRPC status error, so that IsRetryable, RetryDelay and
+	// ErrorInfo can be used on it.
+	Err error
+}
+
+// batchItem is the result of an input in a batch response message.
+type batchItem[P any] interface {
+	GetResponse() P
+	GetError() *spb.Status
+}
+
+// batchResults converts the results of a batch response message, using
+// convert for the responses.
+func batchResults[R batchItem[P], P any, T any](items []R, convert func(P) T) []BatchResult[T] {
+	results := make([]BatchResult[T], len(items))
+	for i, item := range items {
+		if st := item.GetError(); st != nil {
+			results[i].Err = status.ErrorProto(st)
+			continue
+		}
+		results[i].Response = convert(item.GetResponse())
+	}
+	return results
+}
+
+// batchResponses returns the responses of the results, failing with the
+// error of the first failed input, if any.
+func batchResponses[T any](results []BatchResult[T]) ([]T, error) {
+	responses := make([]T, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		responses[i] = result.Response
+	}
+	return responses, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// CircuitBreakerPolicy configures the circuit breaker of a connection: after
+// FailureThreshold consecutive calls failed with a retryable error, the
+// circuit opens, and the calls fail fast with Unavailable for OpenDuration.
+// Then a single trial call is let through, closing the circuit if it gets an
+// answer from the server, or opening it again otherwise.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit. If zero, it is 5.
+	FailureThreshold int
+	// OpenDuration is the time the circuit stays open before a trial call.
+	// If zero, it is 10 seconds.
+	OpenDuration time.Duration
+}
+
+func (p CircuitBreakerPolicy) withDefaults() CircuitBreakerPolicy {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 5
+	}
+	if p.OpenDuration <= 0 {
+		p.OpenDuration = 10 * time.Second
+	}
+	return p
+}
+
+// circuitBreaker is the state of the circuit breaker of a connection.
+type circuitBreaker struct {
+	policy CircuitBreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	// openUntil is the end of the open state, or zero if the circuit is
+	// closed.
+	openUntil time.Time
+	// trial reports whether the trial call of the half-open circuit is in
+	// progress.
+	trial bool
+}
+
+func newCircuitBreaker(p CircuitBreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{policy: p.withDefaults(), now: time.Now}
+}
+
+// allow returns an Unavailable error if the circuit is open, suggesting when
+// to retry. Otherwise, the call can proceed, and its outcome must be passed
+// to record, with whether it is the trial call.
+func (b *circuitBreaker) allow() (trial bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return false, nil
+	}
+	if now := b.now(); b.trial || now.Before(b.openUntil) {
+		delay := max(b.openUntil.Sub(now), 0)
+		st, _ := status.New(codes.Unavailable, "circuit breaker open: too many failures").WithDetails(
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)},
+		)
+		return false, st.Err()
+	}
+	b.trial = true
+	return true, nil
+}
+
+// record records the outcome of a call allowed by allow.
+func (b *circuitBreaker) record(trial bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+	if status.Code(err) == codes.Canceled {
+		// canceled by the caller, before knowing the outcome
+		return
+	}
+	if !IsRetryable(err) {
+		// the server answered, even if with an error
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if trial || b.failures >= b.policy.FailureThreshold {
+		b.openUntil = b.now().Add(b.policy.OpenDuration)
+	}
+}
+
+// breakerUnaryClientInterceptor returns a gRPC interceptor failing the unary
+// calls while the circuit is open.
+func breakerUnaryClientInterceptor(b *circuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		trial, err := b.allow()
+		if err != nil {
+			return err
+		}
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		b.record(trial, err)
+		return err
+	}
+}
+
+// breakerStreamClientInterceptor returns a gRPC interceptor failing the
+// streaming calls while the circuit is open. Only the outcome of opening the
+// stream is recorded.
+func breakerStreamClientInterceptor(b *circuitBreaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		trial, err := b.allow()
+		if err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		b.record(trial, err)
+		return stream, err
+	}
+}
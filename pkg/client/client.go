@@ -0,0 +1,204 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// ErrClosed is returned by the calls of a closed client.
+var ErrClosed = errors.New("client: closed")
+
+// Client is a client of a server, whose task clients share a single
+// connection. It must be closed once it is no longer needed.
+//
+// Client does not implement the task interfaces itself, as some of them have
+// conflicting methods (e.g. Classify): each of its methods returns the task
+// client implementing the interface of a task (e.g. TextEncoding returns a
+// TextEncodingClient, implementing textencoding.Interface).
+type Client struct {
+	conn *connection
+}
+
+// Conn is the connection of a Client to the server: a *grpc.ClientConn, or
+// another transport of the gRPC calls, such as the HTTP/JSON one of the rest
+// package.
+type Conn interface {
+	grpc.ClientConnInterface
+	Close() error
+}
+
+// New creates a client connected to the target.
+//
+// This function blocks until the connection is up, within a timeout of 30
+// seconds.
+func New(ctx context.Context, target string, opts Options) (*Client, error) {
+	conn := &connection{target: target, opts: opts, shared: true}
+	if _, err := conn.get(ctx); err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// NewFromConn creates a client making the calls on the given connection,
+// which is closed by Close. Only the Timeout of the options is used, the
+// others configuring the dialing of gRPC connections.
+func NewFromConn(conn Conn, opts Options) *Client {
+	return &Client{conn: &connection{opts: opts, cc: conn, shared: true}}
+}
+
+// Close closes the connection. The calls in progress are canceled, and the
+// following calls fail with ErrClosed.
+func (c *Client) Close() error {
+	return c.conn.close()
+}
+
+// TextGeneration returns the client for text generation.
+func (c *Client) TextGeneration() TextGenerationClient {
+	return &clientForTextGeneration{conn: c.conn}
+}
+
+// ZeroShotClassification returns the client for zero-shot text
+// classification.
+func (c *Client) ZeroShotClassification() ZeroShotClassificationClient {
+	return &clientForZeroShotClassification{conn: c.conn}
+}
+
+// QuestionAnswering returns the client for extractive question-answering.
+func (c *Client) QuestionAnswering() QuestionAnsweringClient {
+	return &clientForQuestionAnswering{conn: c.conn}
+}
+
+// TextClassification returns the client for text classification.
+func (c *Client) TextClassification() TextClassificationClient {
+	return &clientForTextClassification{conn: c.conn}
+}
+
+// TextEncoding returns the client for text encoding.
+func (c *Client) TextEncoding() TextEncodingClient {
+	return &clientForTextEncoding{conn: c.conn}
+}
+
+// TokenClassification returns the client for token classification.
+func (c *Client) TokenClassification() TokenClassificationClient {
+	return &clientForTokenClassification{conn: c.conn}
+}
+
+// LanguageModeling returns the client for language modeling.
+func (c *Client) LanguageModeling() LanguageModelingClient {
+	return &clientForLanguageModeling{conn: c.conn}
+}
+
+// connection is the connection of the task clients to the server, dialed on
+// the first call and reused by the following ones.
+type connection struct {
+	// target is the server endpoint.
+	target string
+	// opts is the gRPC options for the client.
+	opts Options
+	// shared is set for the connection of a Client, closed by Client.Close
+	// rather than by its task clients.
+	shared bool
+
+	mu     sync.Mutex
+	cc     Conn
+	closed bool
+	// dialing is closed once the dial in progress, if any, is done.
+	dialing chan struct{}
+}
+
+// newConnection returns the connection of a task client created on its own,
+// closed by the Close method of the task client.
+func newConnection(target string, opts Options) *connection {
+	return &connection{target: target, opts: opts}
+}
+
+// get returns the client connection, dialing it if needed. The dial is run
+// without holding the lock, so that closing the connection is not delayed;
+// the concurrent calls wait for it, and dial again if it failed.
+func (c *connection) get(ctx context.Context) (Conn, error) {
+	for {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if c.cc != nil {
+			cc := c.cc
+			c.mu.Unlock()
+			return cc, nil
+		}
+		if c.dialing == nil {
+			return c.dial(ctx)
+		}
+		dialing := c.dialing
+		c.mu.Unlock()
+
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// dial dials the client connection. The caller must hold mu, which is
+// released.
+func (c *connection) dial(ctx context.Context) (Conn, error) {
+	dialing := make(chan struct{})
+	c.dialing = dialing
+	c.mu.Unlock()
+
+	cc, err := Dial(ctx, c.target, c.opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dialing = nil
+	close(dialing)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %q: %w", c.target, err)
+	}
+	if c.closed {
+		_ = cc.Close()
+		return nil, ErrClosed
+	}
+	c.cc = cc
+	return cc, nil
+}
+
+// release closes the connection, unless it is shared by the task clients
+// of a Client.
+func (c *connection) release() error {
+	if c.shared {
+		return nil
+	}
+	return c.close()
+}
+
+func (c *connection) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.cc == nil {
+		return nil
+	}
+	return c.cc.Close()
+}
+
+// callContext returns the context of a unary call, bounded by the configured
+// timeout.
+func (c *connection) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.opts.timeout())
+}
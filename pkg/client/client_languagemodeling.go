@@ -6,40 +6,53 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	languagemodelingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/languagemodeling/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
 )
 
-var _ languagemodeling.Interface = &clientForLanguageModeling{}
+var _ LanguageModelingClient = &clientForLanguageModeling{}
+
+// LanguageModelingClient is a client for language modeling, also predicting
+// the words of many texts with a single batch request.
+type LanguageModelingClient interface {
+	languagemodeling.Interface
+	// BatchPredict predicts the words of each of the given texts, returning
+	// the response or the error of each of them, in the same order.
+	BatchPredict(ctx context.Context, texts []string, parameters languagemodeling.Parameters) ([]BatchResult[languagemodeling.Response], error)
+	// Close closes the connection of a client created by NewClientForLanguageModeling.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForLanguageModeling is a client for language modeling implementing languagemodeling.Interface
 type clientForLanguageModeling struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForLanguageModeling creates a new client for language modeling.
-func NewClientForLanguageModeling(target string, opts Options) languagemodeling.Interface {
-	return &clientForLanguageModeling{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForLanguageModeling(target string, opts Options) LanguageModelingClient {
+	return &clientForLanguageModeling{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForLanguageModeling) Close() error {
+	return c.conn.release()
 }
 
 // Predict predicts the words according to the language modeling architecture.
 func (c *clientForLanguageModeling) Predict(ctx context.Context, text string, parameters languagemodeling.Parameters) (languagemodeling.Response, error) {
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return languagemodeling.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return languagemodeling.Response{}, err
 	}
 	cc := languagemodelingv1.NewLanguageModelingServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Predict(ctx, &languagemodelingv1.LanguageModelingRequest{
@@ -51,8 +64,36 @@ func (c *clientForLanguageModeling) Predict(ctx context.Context, text string, pa
 	if err != nil {
 		return languagemodeling.Response{}, err
 	}
+	return languageModelingResponse(response), nil
+}
+
+// BatchPredict predicts the words of each of the given texts with a single
+// request.
+func (c *clientForLanguageModeling) BatchPredict(ctx context.Context, texts []string, parameters languagemodeling.Parameters) ([]BatchResult[languagemodeling.Response], error) {
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := languagemodelingv1.NewLanguageModelingServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchPredict(ctx, &languagemodelingv1.BatchLanguageModelingRequest{
+		Inputs: texts,
+		Parameters: &languagemodelingv1.LanguageModelingParameters{
+			K: int32(parameters.K),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), languageModelingResponse), nil
+}
+
+func languageModelingResponse(response *languagemodelingv1.LanguageModelingResponse) languagemodeling.Response {
 	if response.GetTokens() == nil {
-		return languagemodeling.Response{}, nil
+		return languagemodeling.Response{}
 	}
 
 	tokens := make([]languagemodeling.Token, len(response.Tokens))
@@ -66,5 +107,5 @@ func (c *clientForLanguageModeling) Predict(ctx context.Context, text string, pa
 	}
 	return languagemodeling.Response{
 		Tokens: tokens,
-	}, nil
+	}
 }
@@ -6,30 +6,50 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	questionansweringnv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/questionanswering/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
 	"github.com/yinziyang/cybertron/pkg/utils/ptr"
 )
 
-var _ questionanswering.Interface = &clientForQuestionAnswering{}
+var _ QuestionAnsweringClient = &clientForQuestionAnswering{}
+
+// QuestionAnsweringClient is a client for extractive question-answering,
+// also answering many questions with a single batch request.
+type QuestionAnsweringClient interface {
+	questionanswering.Interface
+	// BatchExtractAnswer answers each of the given questions, returning the
+	// response or the error of each of them, in the same order.
+	BatchExtractAnswer(ctx context.Context, inputs []QuestionAnsweringInput, opts *questionanswering.Options) ([]BatchResult[questionanswering.Response], error)
+	// Close closes the connection of a client created by NewClientForQuestionAnswering.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
+
+// QuestionAnsweringInput is a question to answer from a passage, as an input
+// of a batch request.
+type QuestionAnsweringInput struct {
+	Question string
+	Passage  string
+}
 
 // clientForQuestionAnswering is a client for question-answering implementing questionanswering.Interface
 type clientForQuestionAnswering struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForQuestionAnswering creates a new client for extractive question-answering.
-func NewClientForQuestionAnswering(target string, opts Options) questionanswering.Interface {
-	return &clientForQuestionAnswering{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForQuestionAnswering(target string, opts Options) QuestionAnsweringClient {
+	return &clientForQuestionAnswering{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForQuestionAnswering) Close() error {
+	return c.conn.release()
 }
 
 // ExtractAnswer answers the given question.
@@ -38,31 +58,86 @@ func (c *clientForQuestionAnswering) ExtractAnswer(ctx context.Context, question
 		opts = &questionanswering.Options{}
 	}
 
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return questionanswering.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return questionanswering.Response{}, err
 	}
 	cc := questionansweringnv1.NewQuestionAnsweringServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
-	response, err := cc.Answer(ctx, &questionansweringnv1.AnswerRequest{
+	response, err := cc.ExtractAnswer(ctx, &questionansweringnv1.AnswerRequest{
 		Question: question,
 		Passage:  passage,
-		Options: &questionansweringnv1.QuestionAnsweringOptions{
-			MaxAnswers:    ptr.Of[int64](int64(opts.MaxAnswers)),
-			MaxAnswersLen: ptr.Of[int64](int64(opts.MaxAnswerLength)),
-			MaxCandidates: ptr.Of[int64](int64(opts.MaxCandidates)),
-			MinScore:      ptr.Of[float64](opts.MinScore),
-		},
+		Options:  answerOptions(opts),
 	})
 	if err != nil {
 		return questionanswering.Response{}, err
 	}
 
-	answers := make([]questionanswering.Answer, len(response.Answers))
-	for i, answer := range response.Answers {
+	return answerResponse(response), nil
+}
+
+// BatchExtractAnswer answers each of the given questions with a single
+// request.
+func (c *clientForQuestionAnswering) BatchExtractAnswer(ctx context.Context, inputs []QuestionAnsweringInput, opts *questionanswering.Options) ([]BatchResult[questionanswering.Response], error) {
+	if opts == nil {
+		opts = &questionanswering.Options{}
+	}
+
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := questionansweringnv1.NewQuestionAnsweringServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	req := &questionansweringnv1.BatchAnswerRequest{
+		Inputs:  make([]*questionansweringnv1.AnswerInput, len(inputs)),
+		Options: answerOptions(opts),
+	}
+	for i, input := range inputs {
+		req.Inputs[i] = &questionansweringnv1.AnswerInput{
+			Question: input.Question,
+			Passage:  input.Passage,
+		}
+	}
+	response, err := cc.BatchExtractAnswer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), answerResponse), nil
+}
+
+// ExtractAnswerBatch answers each of the given questions, failing if any of
+// them cannot be answered.
+func (c *clientForQuestionAnswering) ExtractAnswerBatch(ctx context.Context, inputs []questionanswering.Input, opts *questionanswering.Options) ([]questionanswering.Response, error) {
+	batch := make([]QuestionAnsweringInput, len(inputs))
+	for i, input := range inputs {
+		batch[i] = QuestionAnsweringInput{Question: input.Question, Passage: input.Passage}
+	}
+	results, err := c.BatchExtractAnswer(ctx, batch, opts)
+	if err != nil {
+		return nil, err
+	}
+	return batchResponses(results)
+}
+
+func answerOptions(opts *questionanswering.Options) *questionansweringnv1.QuestionAnsweringOptions {
+	return &questionansweringnv1.QuestionAnsweringOptions{
+		MaxAnswers:    ptr.Of[int64](int64(opts.MaxAnswers)),
+		MaxAnswersLen: ptr.Of[int64](int64(opts.MaxAnswerLength)),
+		MaxCandidates: ptr.Of[int64](int64(opts.MaxCandidates)),
+		MinScore:      ptr.Of[float64](opts.MinScore),
+	}
+}
+
+func answerResponse(response *questionansweringnv1.AnswerResponse) questionanswering.Response {
+	answers := make([]questionanswering.Answer, len(response.GetAnswers()))
+	for i, answer := range response.GetAnswers() {
 		answers[i] = questionanswering.Answer{
 			Text:  answer.Text,
 			Start: int(answer.Start),
@@ -70,5 +145,5 @@ func (c *clientForQuestionAnswering) ExtractAnswer(ctx context.Context, question
 			Score: answer.Score,
 		}
 	}
-	return questionanswering.Response{Answers: answers}, nil
+	return questionanswering.Response{Answers: answers}
 }
@@ -0,0 +1,222 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeInvoker returns a grpc.UnaryInvoker calling fn with the number of the
+// attempt, starting from 1.
+func fakeInvoker(fn func(ctx context.Context, attempt int, reply *textencodingv1.EncodingResponse) error) (grpc.UnaryInvoker, *atomic.Int32) {
+	var attempts atomic.Int32
+	return func(ctx context.Context, _ string, _, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		return fn(ctx, int(attempts.Add(1)), reply.(*textencodingv1.EncodingResponse))
+	}, &attempts
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	interceptor := retryUnaryClientInterceptor(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	ctx := context.Background()
+	call := func(invoker grpc.UnaryInvoker) error {
+		return interceptor(ctx, "/test", nil, &textencodingv1.EncodingResponse{}, nil, invoker)
+	}
+
+	t.Run("retryable", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(_ context.Context, attempt int, _ *textencodingv1.EncodingResponse) error {
+			if attempt < 3 {
+				return status.Error(codes.Unavailable, "unavailable")
+			}
+			return nil
+		})
+		assert.NoError(t, call(invoker))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+	t.Run("max attempts", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(context.Context, int, *textencodingv1.EncodingResponse) error {
+			return status.Error(codes.ResourceExhausted, "busy")
+		})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(call(invoker)))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+	t.Run("permanent", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(context.Context, int, *textencodingv1.EncodingResponse) error {
+			return status.Error(codes.InvalidArgument, "too long")
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(call(invoker)))
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+	t.Run("suggested delay beyond the deadline", func(t *testing.T) {
+		st, err := status.New(codes.Unavailable, "not ready").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		invoker, attempts := fakeInvoker(func(context.Context, int, *textencodingv1.EncodingResponse) error {
+			return st.Err()
+		})
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		err = interceptor(ctx, "/test", nil, &textencodingv1.EncodingResponse{}, nil, invoker)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(1), attempts.Load(), "gives up without waiting")
+	})
+}
+
+func TestHedgingUnaryClientInterceptor(t *testing.T) {
+	interceptor := hedgingUnaryClientInterceptor(HedgingPolicy{MaxAttempts: 3, Delay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	t.Run("slow first attempt", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(ctx context.Context, attempt int, reply *textencodingv1.EncodingResponse) error {
+			if attempt == 1 {
+				<-ctx.Done() // canceled once the second attempt succeeds
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			reply.Vector = []float32{float32(attempt)}
+			return nil
+		})
+		reply := &textencodingv1.EncodingResponse{}
+		require.NoError(t, interceptor(ctx, "/test", nil, reply, nil, invoker))
+		assert.Equal(t, []float32{2}, reply.Vector)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+	t.Run("retryable failures", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(context.Context, int, *textencodingv1.EncodingResponse) error {
+			return status.Error(codes.Unavailable, "unavailable")
+		})
+		err := interceptor(ctx, "/test", nil, &textencodingv1.EncodingResponse{}, nil, invoker)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+	t.Run("permanent", func(t *testing.T) {
+		invoker, attempts := fakeInvoker(func(context.Context, int, *textencodingv1.EncodingResponse) error {
+			return status.Error(codes.InvalidArgument, "too long")
+		})
+		err := interceptor(ctx, "/test", nil, &textencodingv1.EncodingResponse{}, nil, invoker)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 2, OpenDuration: time.Minute})
+	b.now = func() time.Time { return now }
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	call := func(err error) error {
+		trial, allowErr := b.allow()
+		if allowErr != nil {
+			return allowErr
+		}
+		b.record(trial, err)
+		return err
+	}
+
+	assert.Equal(t, unavailable, call(unavailable))
+	assert.NoError(t, call(nil), "a success resets the failures")
+	assert.Equal(t, unavailable, call(unavailable))
+	assert.Equal(t, unavailable, call(unavailable), "opens the circuit")
+
+	err := call(nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err), "fails fast")
+	delay, ok := RetryDelay(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, delay)
+
+	now = now.Add(time.Minute)
+	trial, err := b.allow()
+	require.NoError(t, err)
+	assert.True(t, trial)
+	_, err = b.allow()
+	assert.Error(t, err, "a single trial call at a time")
+	b.record(trial, unavailable)
+	_, err = b.allow()
+	assert.Error(t, err, "the failed trial opens the circuit again")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, codes.InvalidArgument, status.Code(call(status.Error(codes.InvalidArgument, "too long"))))
+	assert.NoError(t, call(nil), "an answer of the server closes the circuit")
+}
+
+func TestUnaryClientInterceptors(t *testing.T) {
+	_, err := unaryClientInterceptors(Options{Retry: &RetryPolicy{}, Hedging: &HedgingPolicy{}}, nil)
+	assert.Error(t, err)
+}
+
+// fakeConn is a Conn recording whether it is closed.
+type fakeConn struct {
+	grpc.ClientConnInterface
+	closed bool
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestConnection_Close(t *testing.T) {
+	t.Run("task client", func(t *testing.T) {
+		c := NewClientForTextEncoding("127.0.0.1:1", Options{})
+		require.NoError(t, c.Close())
+		_, err := c.Encode(context.Background(), "a", 0)
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		conn := &fakeConn{}
+		c := NewFromConn(conn, Options{})
+		require.NoError(t, c.TextEncoding().Close())
+		assert.False(t, conn.closed, "the task clients do not close the shared connection")
+		require.NoError(t, c.Close())
+		assert.True(t, conn.closed)
+	})
+
+	t.Run("while dialing", func(t *testing.T) {
+		// The listener never answers, so the dial blocks until canceled.
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer lis.Close()
+
+		c := newConnection(lis.Addr().String(), Options{})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		dialed := make(chan error, 1)
+		go func() {
+			_, err := c.get(ctx)
+			dialed <- err
+		}()
+		assert.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.dialing != nil
+		}, time.Second, time.Millisecond)
+
+		closed := make(chan error, 1)
+		go func() { closed <- c.close() }()
+		select {
+		case err := <-closed:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("close waited for the dial")
+		}
+
+		cancel()
+		assert.Error(t, <-dialed)
+		_, err = c.get(context.Background())
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+}
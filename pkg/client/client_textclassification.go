@@ -6,40 +6,53 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	textclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textclassification/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
 )
 
-var _ textclassification.Interface = &clientForTextClassification{}
+var _ TextClassificationClient = &clientForTextClassification{}
+
+// TextClassificationClient is a client for text classification, also
+// classifying many texts with a single batch request.
+type TextClassificationClient interface {
+	textclassification.Interface
+	// BatchClassify classifies each of the given texts, returning the
+	// response or the error of each of them, in the same order.
+	BatchClassify(ctx context.Context, texts []string) ([]BatchResult[textclassification.Response], error)
+	// Close closes the connection of a client created by NewClientForTextClassification.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForTextClassification is a client for text classification implementing textclassification.Interface
 type clientForTextClassification struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForTextClassification creates a new client for text classification.
-func NewClientForTextClassification(target string, opts Options) textclassification.Interface {
-	return &clientForTextClassification{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForTextClassification(target string, opts Options) TextClassificationClient {
+	return &clientForTextClassification{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForTextClassification) Close() error {
+	return c.conn.release()
 }
 
 // Classify classifies the given text.
 func (c *clientForTextClassification) Classify(ctx context.Context, text string) (textclassification.Response, error) {
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return textclassification.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return textclassification.Response{}, err
 	}
 	cc := textclassificationv1.NewTextClassificationServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Classify(ctx, &textclassificationv1.ClassifyRequest{
@@ -48,8 +61,42 @@ func (c *clientForTextClassification) Classify(ctx context.Context, text string)
 	if err != nil {
 		return textclassification.Response{}, err
 	}
+	return textClassifyResponse(response), nil
+}
+
+// ClassifyBatch classifies each of the given texts, failing if any of them
+// cannot be classified.
+func (c *clientForTextClassification) ClassifyBatch(ctx context.Context, texts []string) ([]textclassification.Response, error) {
+	results, err := c.BatchClassify(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	return batchResponses(results)
+}
+
+// BatchClassify classifies each of the given texts with a single request.
+func (c *clientForTextClassification) BatchClassify(ctx context.Context, texts []string) ([]BatchResult[textclassification.Response], error) {
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := textclassificationv1.NewTextClassificationServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchClassify(ctx, &textclassificationv1.BatchClassifyRequest{
+		Inputs: texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), textClassifyResponse), nil
+}
+
+func textClassifyResponse(response *textclassificationv1.ClassifyResponse) textclassification.Response {
 	return textclassification.Response{
-		Labels: response.Labels,
-		Scores: response.Scores,
-	}, nil
+		Labels: response.GetLabels(),
+		Scores: response.GetScores(),
+	}
 }
@@ -6,41 +6,54 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
+	"github.com/nlpodyssey/spago/mat"
 	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
-	"github.com/nlpodyssey/spago/mat"
 )
 
-var _ textencoding.Interface = &clientForTextEncoding{}
+var _ TextEncodingClient = &clientForTextEncoding{}
+
+// TextEncodingClient is a client for text encoding, also encoding many texts
+// with a single batch request.
+type TextEncodingClient interface {
+	textencoding.Interface
+	// BatchEncode encodes each of the given texts, returning the response or
+	// the error of each of them, in the same order.
+	BatchEncode(ctx context.Context, texts []string, poolingStrategy int) ([]BatchResult[textencoding.Response], error)
+	// Close closes the connection of a client created by NewClientForTextEncoding.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForTextEncoding is a client for text classification implementing textencoding.Interface
 type clientForTextEncoding struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForTextEncoding creates a new client for text classification.
-func NewClientForTextEncoding(target string, opts Options) textencoding.Interface {
-	return &clientForTextEncoding{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForTextEncoding(target string, opts Options) TextEncodingClient {
+	return &clientForTextEncoding{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForTextEncoding) Close() error {
+	return c.conn.release()
 }
 
 // Encode returns the encoded representation of the given text.
 func (c *clientForTextEncoding) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return textencoding.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return textencoding.Response{}, err
 	}
 	cc := textencodingv1.NewTextEncodingServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Encode(ctx, &textencodingv1.EncodingRequest{
@@ -50,7 +63,42 @@ func (c *clientForTextEncoding) Encode(ctx context.Context, text string, pooling
 	if err != nil {
 		return textencoding.Response{}, err
 	}
+	return encodingResponse(response), nil
+}
+
+// EncodeBatch returns the encoded representation of each of the given texts,
+// failing if any of them cannot be encoded.
+func (c *clientForTextEncoding) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	results, err := c.BatchEncode(ctx, texts, poolingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	return batchResponses(results)
+}
+
+// BatchEncode encodes each of the given texts with a single request.
+func (c *clientForTextEncoding) BatchEncode(ctx context.Context, texts []string, poolingStrategy int) ([]BatchResult[textencoding.Response], error) {
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := textencodingv1.NewTextEncodingServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchEncode(ctx, &textencodingv1.BatchEncodingRequest{
+		Inputs:          texts,
+		PoolingStrategy: int32(poolingStrategy),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), encodingResponse), nil
+}
+
+func encodingResponse(response *textencodingv1.EncodingResponse) textencoding.Response {
 	return textencoding.Response{
-		Vector: mat.NewDense[float32](mat.WithBacking(response.Vector)),
-	}, nil
+		Vector: mat.NewDense[float32](mat.WithBacking(response.GetVector())),
+	}
 }
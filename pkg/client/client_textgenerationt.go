@@ -6,30 +6,44 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
+	"io"
 
 	textgenerationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textgeneration/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
 	"github.com/yinziyang/cybertron/pkg/utils/nullable"
 )
 
-var _ textgeneration.Interface = &clientForTextGeneration{}
+var _ TextGenerationClient = &clientForTextGeneration{}
+
+// TextGenerationClient is a client for text generation, also generating from
+// many inputs with a single batch request.
+type TextGenerationClient interface {
+	textgeneration.Interface
+	// BatchGenerate generates text from each of the given inputs, returning
+	// the response or the error of each of them, in the same order.
+	BatchGenerate(ctx context.Context, texts []string, opts *textgeneration.Options) ([]BatchResult[textgeneration.Response], error)
+	// Close closes the connection of a client created by NewClientForTextGeneration.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForTextGeneration is a client for text generation implementing textgeneration.Interface
 type clientForTextGeneration struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForTextGeneration creates a new client for text generation.
-func NewClientForTextGeneration(target string, opts Options) textgeneration.Interface {
-	return &clientForTextGeneration{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForTextGeneration(target string, opts Options) TextGenerationClient {
+	return &clientForTextGeneration{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForTextGeneration) Close() error {
+	return c.conn.release()
 }
 
 // Generate generates text (e.g. translation, summarization, paraphrase) from the given input.
@@ -37,34 +51,147 @@ func (c *clientForTextGeneration) Generate(ctx context.Context, text string, opt
 	if opts == nil {
 		opts = textgeneration.DefaultOptions()
 	}
-	topK64 := nullable.Type[int64]{
-		Value: int64(opts.TopK.Value),
-		Valid: opts.TopK.Valid,
-	}
 
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return textgeneration.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return textgeneration.Response{}, err
 	}
 	cc := textgenerationv1.NewTextGenerationServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Generate(ctx, &textgenerationv1.GenerateRequest{
-		Input: text,
-		Parameters: &textgenerationv1.TextGenerationParameters{
-			Temperature: opts.Temperature.ValuePtr(),
-			DoSample:    opts.Sample.ValuePtr(),
-			TopK:        topK64.ValuePtr(),
-			TopP:        opts.TopP.ValuePtr(),
-		},
+		Input:      text,
+		Parameters: generationParameters(opts),
 	})
 	if err != nil {
 		return textgeneration.Response{}, err
 	}
+	return generateResponse(response), nil
+}
+
+// BatchGenerate generates text from each of the given inputs with a single
+// request.
+func (c *clientForTextGeneration) BatchGenerate(ctx context.Context, texts []string, opts *textgeneration.Options) ([]BatchResult[textgeneration.Response], error) {
+	if opts == nil {
+		opts = textgeneration.DefaultOptions()
+	}
+
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := textgenerationv1.NewTextGenerationServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchGenerate(ctx, &textgenerationv1.BatchGenerateRequest{
+		Inputs:     texts,
+		Parameters: generationParameters(opts),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), generateResponse), nil
+}
+
+// GenerateStream generates text from the input, streaming the partial results.
+// The stream is not bounded by the configured timeout.
+func (c *clientForTextGeneration) GenerateStream(ctx context.Context, text string, opts *textgeneration.Options) (<-chan textgeneration.Chunk, error) {
+	if opts == nil {
+		opts = textgeneration.DefaultOptions()
+	}
+
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := textgenerationv1.NewTextGenerationServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := cc.GenerateStream(ctx, &textgenerationv1.GenerateRequest{
+		Input:      text,
+		Parameters: generationParameters(opts),
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	chunks := make(chan textgeneration.Chunk)
+	send := func(c textgeneration.Chunk) bool {
+		select {
+		case chunks <- c:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(chunks)
+		defer cancel()
+
+		for {
+			response, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				send(textgeneration.Chunk{Final: true, Err: err})
+				return
+			}
+			chunk := textgeneration.Chunk{
+				Text:  response.GetText(),
+				Final: response.GetFinal(),
+			}
+			if result := response.GetResult(); result != nil {
+				chunk.Response = generateResponse(result)
+			}
+			if !send(chunk) {
+				return
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
+func generateResponse(response *textgenerationv1.GenerateResponse) textgeneration.Response {
 	return textgeneration.Response{
-		Texts:  response.Texts,
-		Scores: response.Scores,
-	}, nil
+		Texts:  response.GetTexts(),
+		Scores: response.GetScores(),
+	}
+}
+
+// generationParameters converts the textgeneration.Options to the request parameters.
+func generationParameters(opts *textgeneration.Options) *textgenerationv1.TextGenerationParameters {
+	return &textgenerationv1.TextGenerationParameters{
+		Temperature: opts.Temperature.ValuePtr(),
+		DoSample:    opts.Sample.ValuePtr(),
+		TopK:        int64Ptr(opts.TopK),
+		TopP:        opts.TopP.ValuePtr(),
+
+		NumBeams:           int64Ptr(opts.NumBeams),
+		MinLength:          int64Ptr(opts.MinLength),
+		MaxLength:          int64Ptr(opts.MaxLength),
+		MaxNewTokens:       int64Ptr(opts.MaxNewTokens),
+		LengthPenalty:      opts.LengthPenalty.ValuePtr(),
+		EarlyStopping:      opts.EarlyStopping.ValuePtr(),
+		NoRepeatNgramSize:  int64Ptr(opts.NoRepeatNGramSize),
+		NumReturnSequences: int64Ptr(opts.NumReturnSequences),
+		StopSequences:      opts.StopSequences,
+		BadWords:           opts.BadWords,
+	}
+}
+
+// int64Ptr converts a nullable int to a pointer to int64, as used in the requests.
+func int64Ptr(v nullable.Type[int]) *int64 {
+	v64 := nullable.Type[int64]{
+		Value: int64(v.Value),
+		Valid: v.Valid,
+	}
+	return v64.ValuePtr()
 }
@@ -7,39 +7,53 @@ package client
 import (
 	"context"
 	"fmt"
-	"time"
 
 	tokenclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/tokenclassification/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
 )
 
-var _ tokenclassification.Interface = &clientForTokenClassification{}
+var _ TokenClassificationClient = &clientForTokenClassification{}
+
+// TokenClassificationClient is a client for token classification, also
+// classifying many texts with a single batch request.
+type TokenClassificationClient interface {
+	tokenclassification.Interface
+	// BatchClassify classifies each of the given texts, returning the
+	// response or the error of each of them, in the same order.
+	BatchClassify(ctx context.Context, texts []string, parameters tokenclassification.Parameters) ([]BatchResult[tokenclassification.Response], error)
+	// Close closes the connection of a client created by NewClientForTokenClassification.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForTextClassification is a client for token classification implementing tokenclassification.Interface
 type clientForTokenClassification struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForTokenClassification creates a new client for token classification.
-func NewClientForTokenClassification(target string, opts Options) tokenclassification.Interface {
-	return &clientForTokenClassification{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForTokenClassification(target string, opts Options) TokenClassificationClient {
+	return &clientForTokenClassification{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForTokenClassification) Close() error {
+	return c.conn.release()
 }
 
 // Classify classifies the given text.
 func (c *clientForTokenClassification) Classify(ctx context.Context, text string, parameters tokenclassification.Parameters) (tokenclassification.Response, error) {
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return tokenclassification.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return tokenclassification.Response{}, err
 	}
 	cc := tokenclassificationv1.NewTokenClassificationServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Classify(ctx, &tokenclassificationv1.ClassifyRequest{
@@ -49,8 +63,43 @@ func (c *clientForTokenClassification) Classify(ctx context.Context, text string
 	if err != nil {
 		return tokenclassification.Response{}, err
 	}
+	return tokenClassifyResponse(response), nil
+}
+
+// ClassifyBatch classifies each of the given texts, failing if any of them
+// cannot be classified.
+func (c *clientForTokenClassification) ClassifyBatch(ctx context.Context, texts []string, parameters tokenclassification.Parameters) ([]tokenclassification.Response, error) {
+	results, err := c.BatchClassify(ctx, texts, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return batchResponses(results)
+}
+
+// BatchClassify classifies each of the given texts with a single request.
+func (c *clientForTokenClassification) BatchClassify(ctx context.Context, texts []string, parameters tokenclassification.Parameters) ([]BatchResult[tokenclassification.Response], error) {
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := tokenclassificationv1.NewTokenClassificationServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchClassify(ctx, &tokenclassificationv1.BatchClassifyRequest{
+		Inputs:              texts,
+		AggregationStrategy: grpcAggregationStrategy(parameters.AggregationStrategy),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), tokenClassifyResponse), nil
+}
+
+func tokenClassifyResponse(response *tokenclassificationv1.ClassifyResponse) tokenclassification.Response {
 	if response.GetTokens() == nil {
-		return tokenclassification.Response{}, nil
+		return tokenclassification.Response{}
 	}
 
 	tokens := make([]tokenclassification.Token, len(response.Tokens))
@@ -65,7 +114,7 @@ func (c *clientForTokenClassification) Classify(ctx context.Context, text string
 	}
 	return tokenclassification.Response{
 		Tokens: tokens,
-	}, nil
+	}
 }
 
 func grpcAggregationStrategy(value tokenclassification.AggregationStrategy) tokenclassificationv1.ClassifyRequest_AggregationStrategy {
@@ -6,55 +6,97 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	zeroshottextclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/zeroshot/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
 )
 
-var _ zeroshotclassifier.Interface = &clientForZeroShotClassification{}
+var _ ZeroShotClassificationClient = &clientForZeroShotClassification{}
+
+// ZeroShotClassificationClient is a client for zero-shot text
+// classification, also classifying many texts with a single batch request.
+type ZeroShotClassificationClient interface {
+	zeroshotclassifier.Interface
+	// BatchClassify classifies each of the given texts, returning the
+	// response or the error of each of them, in the same order.
+	BatchClassify(ctx context.Context, texts []string, parameters zeroshotclassifier.Parameters) ([]BatchResult[zeroshotclassifier.Response], error)
+	// Close closes the connection of a client created by NewClientForZeroShotClassification.
+	// It does nothing for the task client of a Client, whose connection is
+	// closed by Client.Close.
+	Close() error
+}
 
 // clientForZeroShotClassification is a client for zero-shot text generation implementing zeroshotclassifier.Interface
 type clientForZeroShotClassification struct {
-	// target is the server endpoint.
-	target string
-	// opts is the gRPC options for the client.
-	opts Options
+	conn *connection
 }
 
 // NewClientForZeroShotClassification creates a new client for zero-shot text classification.
-func NewClientForZeroShotClassification(target string, opts Options) zeroshotclassifier.Interface {
-	return &clientForZeroShotClassification{
-		target: target,
-		opts:   opts,
-	}
+// It owns its connection, which must be closed with Close once the client
+// is no longer needed.
+func NewClientForZeroShotClassification(target string, opts Options) ZeroShotClassificationClient {
+	return &clientForZeroShotClassification{conn: newConnection(target, opts)}
+}
+
+// Close closes the connection of the client, unless it is shared with the
+// other task clients of a Client.
+func (c *clientForZeroShotClassification) Close() error {
+	return c.conn.release()
 }
 
 // Classify classifies the given text.
 func (c *clientForZeroShotClassification) Classify(ctx context.Context, text string, parameters zeroshotclassifier.Parameters) (zeroshotclassifier.Response, error) {
-	conn, err := Dial(ctx, c.target, c.opts)
+	conn, err := c.conn.get(ctx)
 	if err != nil {
-		return zeroshotclassifier.Response{}, fmt.Errorf("failed to dial %q: %w", c.target, err)
+		return zeroshotclassifier.Response{}, err
 	}
 	cc := zeroshottextclassificationv1.NewZeroShotServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := c.conn.callContext(ctx)
 	defer cancel()
 
 	response, err := cc.Classify(ctx, &zeroshottextclassificationv1.ClassifyRequest{
-		Input: text,
-		Parameters: &zeroshottextclassificationv1.ZeroShotParameters{
-			HypothesisTemplate: parameters.HypothesisTemplate,
-			CandidateLabels:    parameters.CandidateLabels,
-			MultiLabel:         parameters.MultiLabel,
-		},
+		Input:      text,
+		Parameters: zeroShotParameters(parameters),
 	})
 	if err != nil {
 		return zeroshotclassifier.Response{}, err
 	}
+	return zeroShotClassifyResponse(response), nil
+}
+
+// BatchClassify classifies each of the given texts with a single request.
+func (c *clientForZeroShotClassification) BatchClassify(ctx context.Context, texts []string, parameters zeroshotclassifier.Parameters) ([]BatchResult[zeroshotclassifier.Response], error) {
+	conn, err := c.conn.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc := zeroshottextclassificationv1.NewZeroShotServiceClient(conn)
+
+	ctx, cancel := c.conn.callContext(ctx)
+	defer cancel()
+
+	response, err := cc.BatchClassify(ctx, &zeroshottextclassificationv1.BatchClassifyRequest{
+		Inputs:     texts,
+		Parameters: zeroShotParameters(parameters),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(response.GetResults(), zeroShotClassifyResponse), nil
+}
+
+func zeroShotParameters(parameters zeroshotclassifier.Parameters) *zeroshottextclassificationv1.ZeroShotParameters {
+	return &zeroshottextclassificationv1.ZeroShotParameters{
+		HypothesisTemplate: parameters.HypothesisTemplate,
+		CandidateLabels:    parameters.CandidateLabels,
+		MultiLabel:         parameters.MultiLabel,
+	}
+}
+
+func zeroShotClassifyResponse(response *zeroshottextclassificationv1.ClassifyResponse) zeroshotclassifier.Response {
 	return zeroshotclassifier.Response{
-		Labels: response.Labels,
-		Scores: response.Scores,
-	}, nil
+		Labels: response.GetLabels(),
+		Scores: response.GetScores(),
+	}
 }
@@ -6,13 +6,18 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer/roundrobin"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // loadBalancingConfig is the configuration for the round-robin load balancer.
@@ -20,9 +25,50 @@ var loadBalancingConfig = fmt.Sprintf(`{ "loadBalancingConfig": [{"%v": {}}] }`,
 
 // Options are the options for dialing a gRPC client.
 type Options struct {
-	UseTLS        bool
-	CertFile      string
-	UseRoundRobin bool
+	UseTLS bool
+	// CertFile is the file of the CA certificates verifying the server. If
+	// empty, the CAs of the host are used.
+	CertFile string
+	// ClientCertFile and ClientKeyFile are the files of the certificate
+	// presented to the servers requiring mutual TLS. They are read on each
+	// TLS handshake, so that rotated certificates are picked up.
+	ClientCertFile string
+	ClientKeyFile  string
+	UseRoundRobin  bool
+	// APIKey is sent as bearer token with each call, if not empty.
+	APIKey string
+	// Timeout is the deadline of each unary call, including its retries,
+	// unless the context has an earlier one. If zero, it is 30 seconds.
+	Timeout time.Duration
+	// KeepaliveTime is the time after which the client pings the server if
+	// the connection is idle, so that broken connections are detected
+	// without waiting for a call to fail. If zero, keepalive pings are not
+	// sent. Servers close the connections pinging more frequently than
+	// their policy allows, which is every 5 minutes by default.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is the time to wait for the acknowledgement of a
+	// keepalive ping before closing the connection. If zero, it is 20
+	// seconds.
+	KeepaliveTimeout time.Duration
+	// Retry, if not nil, retries the unary calls failed with a retryable
+	// error. It cannot be used together with Hedging.
+	Retry *RetryPolicy
+	// Hedging, if not nil, sends the unary calls again if they are not
+	// answered in time, using the first response.
+	Hedging *HedgingPolicy
+	// CircuitBreaker, if not nil, fails the calls fast while the server
+	// keeps failing.
+	CircuitBreaker *CircuitBreakerPolicy
+}
+
+// defaultTimeout is the default deadline of the unary calls.
+const defaultTimeout = 30 * time.Second
+
+func (o Options) timeout() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return defaultTimeout
 }
 
 // Dial creates a client connection to the configured target, also respecting
@@ -37,23 +83,106 @@ func Dial(ctx context.Context, target string, opts Options) (_ *grpc.ClientConn,
 
 	creds := insecure.NewCredentials()
 	if opts.UseTLS {
-		creds, err = credentials.NewClientTLSFromFile(opts.CertFile, "")
+		creds, err = tlsCredentials(opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct TLS credentials: %v", err)
 		}
 	}
 	grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(creds))
 
+	if opts.APIKey != "" {
+		grpcOpts = append(grpcOpts, grpc.WithPerRPCCredentials(bearerToken(opts.APIKey)))
+	}
+
 	if opts.UseRoundRobin {
 		grpcOpts = append(grpcOpts, grpc.WithDefaultServiceConfig(loadBalancingConfig))
 	}
 
+	if opts.KeepaliveTime > 0 {
+		grpcOpts = append(grpcOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    opts.KeepaliveTime,
+			Timeout: opts.KeepaliveTimeout,
+		}))
+	}
+
+	var breaker *circuitBreaker
+	if opts.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(*opts.CircuitBreaker)
+		grpcOpts = append(grpcOpts, grpc.WithChainStreamInterceptor(breakerStreamClientInterceptor(breaker)))
+	}
+	interceptors, err := unaryClientInterceptors(opts, breaker)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, grpc.WithChainUnaryInterceptor(interceptors...))
+
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	conn, err := grpc.DialContext(ctx, target, grpcOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("error dialing gRPC %+v: %w", opts, err)
+		return nil, fmt.Errorf("error dialing gRPC %s: %w", target, err)
 	}
 	return conn, nil
 }
+
+// unaryClientInterceptors returns the interceptors applying the circuit
+// breaker, the retries and the hedging of the options, from the outermost.
+// The circuit breaker records the outcome of each call after its retries.
+func unaryClientInterceptors(opts Options, breaker *circuitBreaker) ([]grpc.UnaryClientInterceptor, error) {
+	if opts.Retry != nil && opts.Hedging != nil {
+		return nil, errors.New("retry and hedging policies are mutually exclusive")
+	}
+
+	var interceptors []grpc.UnaryClientInterceptor
+	if breaker != nil {
+		interceptors = append(interceptors, breakerUnaryClientInterceptor(breaker))
+	}
+	if opts.Retry != nil {
+		interceptors = append(interceptors, retryUnaryClientInterceptor(*opts.Retry))
+	}
+	if opts.Hedging != nil {
+		interceptors = append(interceptors, hedgingUnaryClientInterceptor(*opts.Hedging))
+	}
+	return interceptors, nil
+}
+
+// tlsCredentials returns the TLS credentials of the options.
+func tlsCredentials(opts Options) (credentials.TransportCredentials, error) {
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CertFile != "" {
+		pem, err := os.ReadFile(opts.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates in %q", opts.CertFile)
+		}
+	}
+	if opts.ClientCertFile != "" {
+		// fail early if the files are not valid
+		if _, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile); err != nil {
+			return nil, err
+		}
+		conf.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+			return &cert, err
+		}
+	}
+	return credentials.NewTLS(conf), nil
+}
+
+// bearerToken is a credentials.PerRPCCredentials sending a static bearer
+// token in the "authorization" metadata.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity returns false, so that the token can also be sent
+// to servers without TLS, e.g. behind a TLS-terminating proxy.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
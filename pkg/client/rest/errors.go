@@ -0,0 +1,90 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxErrorBodySize is the maximum size of the error bodies read.
+const maxErrorBodySize = 64 << 10
+
+// responseError returns the error of a failed response, decoded from the
+// google.rpc.Status of its body, details included, so that the errors can be
+// inspected with client.IsRetryable, client.RetryDelay and client.ErrorInfo
+// as the ones of the gRPC clients.
+//
+// If the body is not a status, e.g. as returned by a proxy, the code of the
+// error is derived from the HTTP status code.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if st, ok := decodeStatus(body); ok {
+		return status.ErrorProto(st)
+	}
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	return status.Errorf(codeFromHTTPStatus(resp.StatusCode), "HTTP %d: %s", resp.StatusCode, msg)
+}
+
+// decodeStatus decodes the google.rpc.Status rendered by the gateway.
+func decodeStatus(data []byte) (*spb.Status, bool) {
+	st := &spb.Status{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, st); err != nil {
+		return nil, false
+	}
+	if st.GetCode() == int32(codes.OK) {
+		return nil, false
+	}
+	return st, true
+}
+
+// codeFromHTTPStatus maps the HTTP status codes to the gRPC codes, inverting
+// the mapping of the gateway.
+func codeFromHTTPStatus(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		if code >= 500 {
+			return codes.Internal
+		}
+		return codes.Unknown
+	}
+}
+
+// transportError returns the error of a request failed without a response,
+// which is Unavailable unless caused by the context.
+func transportError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rest provides the clients of the tasks over the HTTP/JSON routes
+// of the REST gateway (e.g. "/v1/encode"), for the consumers that cannot use
+// gRPC, such as the ones behind proxies allowing HTTP/1.1 only.
+//
+// The clients are the ones of the client package, making their calls over
+// HTTP instead of gRPC, so they implement the same task interfaces.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yinziyang/cybertron/pkg/client"
+	languagemodelingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/languagemodeling/v1"
+	questionansweringv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/questionanswering/v1"
+	textclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textclassification/v1"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	textgenerationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textgeneration/v1"
+	tokenclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/tokenclassification/v1"
+	zeroshotv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/zeroshot/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Options are the options of a REST client.
+type Options struct {
+	// TLSConfig is the TLS configuration of the "https" base URLs, e.g. with
+	// the CAs verifying the server or the certificate of the client. If nil,
+	// the default configuration is used.
+	TLSConfig *tls.Config
+	// APIKey is sent as bearer token with each request, if not empty.
+	APIKey string
+	// Header is sent with each request, e.g. with the headers required by a
+	// proxy.
+	Header http.Header
+	// Timeout is the deadline of each request, except the streaming ones,
+	// unless the context has an earlier one. If zero, it is 30 seconds.
+	Timeout time.Duration
+	// HTTPClient, if not nil, sends the requests, instead of a client
+	// configured with TLSConfig. Its Timeout must be zero, so that the
+	// streams are not interrupted.
+	HTTPClient *http.Client
+}
+
+// New creates a client of the REST gateway at the base URL, such as
+// "https://example.com" or "http://localhost:8080/cybertron". It must be
+// closed once it is no longer needed.
+func New(baseURL string, opts Options) (*client.Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: the scheme must be http or https", baseURL)
+	}
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = opts.TLSConfig
+		httpClient = &http.Client{Transport: transport}
+	}
+
+	c := &conn{
+		baseURL: strings.TrimSuffix(u.String(), "/"),
+		http:    httpClient,
+		opts:    opts,
+	}
+	return client.NewFromConn(c, client.Options{Timeout: opts.Timeout}), nil
+}
+
+// routes are the HTTP paths of the gRPC methods. The classification methods
+// use the task-specific paths, rather than "/v1/classify", which is shared
+// by the tasks.
+var routes = map[string]string{
+	languagemodelingv1.LanguageModelingService_Predict_FullMethodName:              "/v1/predict",
+	languagemodelingv1.LanguageModelingService_BatchPredict_FullMethodName:         "/v1/predict:batch",
+	questionansweringv1.QuestionAnsweringService_ExtractAnswer_FullMethodName:      "/v1/answer",
+	questionansweringv1.QuestionAnsweringService_BatchExtractAnswer_FullMethodName: "/v1/answer:batch",
+	textclassificationv1.TextClassificationService_Classify_FullMethodName:         "/v1/text-classification/classify",
+	textclassificationv1.TextClassificationService_BatchClassify_FullMethodName:    "/v1/text-classification/classify:batch",
+	textencodingv1.TextEncodingService_Encode_FullMethodName:                       "/v1/encode",
+	textencodingv1.TextEncodingService_BatchEncode_FullMethodName:                  "/v1/encode:batch",
+	textgenerationv1.TextGenerationService_Generate_FullMethodName:                 "/v1/generate",
+	textgenerationv1.TextGenerationService_BatchGenerate_FullMethodName:            "/v1/generate:batch",
+	textgenerationv1.TextGenerationService_GenerateStream_FullMethodName:           "/v1/generate:stream",
+	tokenclassificationv1.TokenClassificationService_Classify_FullMethodName:       "/v1/token-classification/classify",
+	tokenclassificationv1.TokenClassificationService_BatchClassify_FullMethodName:  "/v1/token-classification/classify:batch",
+	zeroshotv1.ZeroShotService_Classify_FullMethodName:                             "/v1/zero-shot-classification/classify",
+	zeroshotv1.ZeroShotService_BatchClassify_FullMethodName:                        "/v1/zero-shot-classification/classify:batch",
+}
+
+// conn is a client.Conn making the gRPC calls as HTTP requests to the REST
+// gateway.
+type conn struct {
+	baseURL string
+	http    *http.Client
+	opts    Options
+}
+
+var _ client.Conn = &conn{}
+
+// Invoke sends the request of a unary call, decoding the response into
+// reply.
+func (c *conn) Invoke(ctx context.Context, method string, args, reply any, _ ...grpc.CallOption) error {
+	resp, err := c.post(ctx, method, args)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return transportError(ctx, err)
+	}
+	if err := unmarshal(body, reply); err != nil {
+		return status.Errorf(codes.Internal, "failed to decode the response: %v", err)
+	}
+	return nil
+}
+
+// NewStream starts a server-streaming call, whose request is sent as soon as
+// it is closed by the generated client.
+func (c *conn) NewStream(ctx context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+	return &eventStream{ctx: ctx, conn: c, method: method}, nil
+}
+
+// Close closes the idle connections.
+func (c *conn) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// post sends the request of the gRPC method, returning the response if
+// successful, or the error decoded from its body.
+func (c *conn) post(ctx context.Context, method string, args any) (*http.Response, error) {
+	route, ok := routes[method]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "no HTTP route for method %s", method)
+	}
+	msg, ok := args.(proto.Message)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "invalid request type %T", args)
+	}
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode the request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+route, bytes.NewReader(body))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create the request: %v", err)
+	}
+	if c.opts.Header != nil {
+		req.Header = c.opts.Header.Clone()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.opts.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.APIKey)
+	}
+	// the gateway maps these headers to the metadata of the call, e.g. the
+	// model to use
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		for _, v := range vs {
+			req.Header.Add("Grpc-Metadata-"+k, v)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// unmarshal decodes a JSON message of the gateway, ignoring the unknown
+// fields added by newer servers.
+func unmarshal(data []byte, m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("invalid message type %T", m)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+}
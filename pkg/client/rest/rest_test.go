@@ -0,0 +1,176 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/client"
+	"github.com/yinziyang/cybertron/pkg/server"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEncoder fails to encode the text "long", as too long.
+type fakeEncoder struct{}
+
+func (fakeEncoder) Encode(_ context.Context, text string, _ int) (textencoding.Response, error) {
+	if text == "long" {
+		return textencoding.Response{}, taskerrors.InputTooLong(textencoding.ErrInputSequenceTooLong, 600, 512)
+	}
+	return textencoding.Response{Vector: mat.NewDense[float32](mat.WithBacking([]float32{1, 2}))}, nil
+}
+
+func (e fakeEncoder) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	responses := make([]textencoding.Response, len(texts))
+	for i, text := range texts {
+		var err error
+		if responses[i], err = e.Encode(ctx, text, poolingStrategy); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+type fakeClassifier struct{}
+
+func (fakeClassifier) Classify(context.Context, string) (textclassification.Response, error) {
+	return textclassification.Response{Labels: []string{"positive"}, Scores: []float64{1}}, nil
+}
+
+func (c fakeClassifier) ClassifyBatch(ctx context.Context, texts []string) ([]textclassification.Response, error) {
+	responses := make([]textclassification.Response, len(texts))
+	for i, text := range texts {
+		responses[i], _ = c.Classify(ctx, text)
+	}
+	return responses, nil
+}
+
+// fakeGenerator echoes the input, streaming it one word at a time.
+type fakeGenerator struct{}
+
+func (fakeGenerator) Generate(_ context.Context, text string, _ *textgeneration.Options) (textgeneration.Response, error) {
+	return textgeneration.Response{Texts: []string{text}, Scores: []float64{1}}, nil
+}
+
+func (g fakeGenerator) GenerateStream(ctx context.Context, text string, opts *textgeneration.Options) (<-chan textgeneration.Chunk, error) {
+	chunks := make(chan textgeneration.Chunk, 2)
+	response, _ := g.Generate(ctx, text, opts)
+	chunks <- textgeneration.Chunk{Text: "partial"}
+	chunks <- textgeneration.Chunk{Text: text, Final: true, Response: response}
+	close(chunks)
+	return chunks, nil
+}
+
+// startServer starts a server of the models on a random local port,
+// stopping it at the end of the test.
+func startServer(t *testing.T, conf *server.Config, models map[string]any) *server.Server {
+	t.Helper()
+	r := server.NewRegistry()
+	for name, model := range models {
+		require.NoError(t, r.Add(name, model))
+	}
+	conf.Address = "127.0.0.1:0"
+	s := server.New(conf, r)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	require.True(t, s.ReadyForConnections(5*time.Second))
+	return s
+}
+
+func TestClient(t *testing.T) {
+	s := startServer(t, &server.Config{APIKeys: []server.APIKey{{Key: "secret"}}}, map[string]any{
+		"embeddings": fakeEncoder{},
+		"sentiment":  fakeClassifier{},
+		"echo":       fakeGenerator{},
+	})
+	ctx := context.Background()
+
+	c, err := New("http://"+s.ClientAddr()+"/", Options{APIKey: "secret", Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer c.Close()
+
+	t.Run("unary", func(t *testing.T) {
+		response, err := c.TextEncoding().Encode(ctx, "a", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{1, 2}, response.Vector.Data().F32())
+
+		classification, err := c.TextClassification().Classify(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"positive"}, classification.Labels)
+	})
+	t.Run("structured errors", func(t *testing.T) {
+		_, err := c.TextEncoding().Encode(ctx, "long", 0)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		info, ok := client.ErrorInfo(err)
+		require.True(t, ok)
+		assert.Equal(t, server.ReasonInputSequenceTooLong, info.GetReason())
+		assert.False(t, client.IsRetryable(err))
+	})
+	t.Run("batch", func(t *testing.T) {
+		results, err := c.TextEncoding().BatchEncode(ctx, []string{"a", "long"}, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(results[1].Err))
+	})
+	t.Run("stream", func(t *testing.T) {
+		chunks, err := c.TextGeneration().GenerateStream(ctx, "hello world", nil)
+		require.NoError(t, err)
+		var texts []string
+		var last textgeneration.Chunk
+		for chunk := range chunks {
+			require.NoError(t, chunk.Err)
+			texts = append(texts, chunk.Text)
+			last = chunk
+		}
+		assert.Equal(t, []string{"partial", "hello world"}, texts)
+		assert.True(t, last.Final)
+		assert.Equal(t, []string{"hello world"}, last.Response.Texts)
+	})
+	t.Run("unauthenticated", func(t *testing.T) {
+		anonymous, err := New("http://"+s.ClientAddr(), Options{})
+		require.NoError(t, err)
+		defer anonymous.Close()
+		_, err = anonymous.TextEncoding().Encode(ctx, "a", 0)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestClient_ProxyErrors(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "yes", r.Header.Get("X-Proxy-Auth"))
+		http.Error(w, "upstream unreachable", http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	c, err := New(proxy.URL, Options{Header: http.Header{"X-Proxy-Auth": {"yes"}}})
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.TextEncoding().Encode(context.Background(), "a", 0)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), "upstream unreachable")
+	assert.True(t, client.IsRetryable(err))
+
+	_, err = New("localhost:8080", Options{})
+	assert.Error(t, err, "no scheme")
+}
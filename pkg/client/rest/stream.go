@@ -0,0 +1,119 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// eventStream is a grpc.ClientStream of a server-streaming call, receiving
+// the responses as server-sent events. A failure after the stream has
+// started is reported by the server with an "error" event carrying the
+// google.rpc.Status.
+type eventStream struct {
+	ctx    context.Context
+	conn   *conn
+	method string
+
+	req    any
+	resp   *http.Response
+	events *bufio.Reader
+}
+
+// SendMsg keeps the request, sent by CloseSend.
+func (s *eventStream) SendMsg(m any) error {
+	if s.req != nil {
+		return status.Error(codes.Internal, "only server-streaming calls are supported")
+	}
+	s.req = m
+	return nil
+}
+
+// CloseSend sends the request, starting the stream.
+func (s *eventStream) CloseSend() error {
+	resp, err := s.conn.post(s.ctx, s.method, s.req)
+	if err != nil {
+		return err
+	}
+	s.resp = resp
+	s.events = bufio.NewReader(resp.Body)
+	return nil
+}
+
+// RecvMsg decodes the next event into m, returning io.EOF at the end of the
+// stream.
+func (s *eventStream) RecvMsg(m any) error {
+	if s.resp == nil {
+		return status.Error(codes.Internal, "stream not started")
+	}
+	event, data, err := s.next()
+	if err != nil {
+		s.resp.Body.Close()
+		if err == io.EOF {
+			return err
+		}
+		return transportError(s.ctx, err)
+	}
+	if event == "error" {
+		s.resp.Body.Close()
+		if st, ok := decodeStatus([]byte(data)); ok {
+			return status.ErrorProto(st)
+		}
+		return status.Errorf(codes.Unknown, "stream failed: %s", data)
+	}
+	if err := unmarshal([]byte(data), m); err != nil {
+		s.resp.Body.Close()
+		return status.Errorf(codes.Internal, "failed to decode the event: %v", err)
+	}
+	return nil
+}
+
+// next reads the next event with data, returning its type and data.
+func (s *eventStream) next() (event, data string, err error) {
+	var lines []string
+	for {
+		line, err := s.events.ReadString('\n')
+		if err != nil && line == "" {
+			if errors.Is(err, io.EOF) && len(lines) > 0 {
+				return event, strings.Join(lines, "\n"), nil
+			}
+			return "", "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if len(lines) > 0 {
+				return event, strings.Join(lines, "\n"), nil
+			}
+			event = ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+func (s *eventStream) Header() (metadata.MD, error) {
+	return nil, nil
+}
+
+func (s *eventStream) Trailer() metadata.MD {
+	return nil
+}
+
+func (s *eventStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// RetryPolicy configures the retries of the calls failed with a retryable
+// error (see IsRetryable). Each retry waits for a random time up to the
+// current backoff, which grows exponentially, or for the delay suggested by
+// the server, if longer.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. If zero, it is 3.
+	MaxAttempts int
+	// InitialBackoff is the backoff of the first retry. If zero, it is 100
+	// milliseconds.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum backoff. If zero, it is 5 seconds.
+	MaxBackoff time.Duration
+	// Multiplier is the growth of the backoff after each retry. If zero, it
+	// is 2.
+	Multiplier float64
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// HedgingPolicy configures the hedging of the calls: if a call is not
+// answered within Delay, the same request is sent again, up to MaxAttempts
+// concurrent attempts, and the first response is used, canceling the other
+// attempts. An attempt failed with a retryable error starts the next one
+// right away.
+//
+// Hedging trades some load on the servers for a lower tail latency; it is
+// best combined with UseRoundRobin, so that the attempts can reach different
+// servers.
+type HedgingPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. If zero, it is 2.
+	MaxAttempts int
+	// Delay is the time to wait for a response before sending the next
+	// attempt. If zero, it is 100 milliseconds.
+	Delay time.Duration
+}
+
+func (p HedgingPolicy) withDefaults() HedgingPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 2
+	}
+	if p.Delay <= 0 {
+		p.Delay = 100 * time.Millisecond
+	}
+	return p
+}
+
+// retryUnaryClientInterceptor returns a gRPC interceptor retrying the unary
+// calls according to the policy.
+func retryUnaryClientInterceptor(p RetryPolicy) grpc.UnaryClientInterceptor {
+	p = p.withDefaults()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		backoff := p.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || !IsRetryable(err) || attempt >= p.MaxAttempts {
+				return err
+			}
+
+			delay := time.Duration(rand.Int63n(int64(backoff) + 1))
+			if suggested, ok := RetryDelay(err); ok && suggested > delay {
+				delay = suggested
+			}
+			if !sleep(ctx, delay) {
+				return err
+			}
+			backoff = min(time.Duration(float64(backoff)*p.Multiplier), p.MaxBackoff)
+		}
+	}
+}
+
+// sleep waits for the given delay, returning false without waiting if the
+// context is done before the end of it.
+func sleep(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// hedgingUnaryClientInterceptor returns a gRPC interceptor hedging the unary
+// calls according to the policy.
+func hedgingUnaryClientInterceptor(p HedgingPolicy) grpc.UnaryClientInterceptor {
+	p = p.withDefaults()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		replyMsg, ok := reply.(proto.Message)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // cancels the attempts still in progress
+
+		type result struct {
+			reply proto.Message
+			err   error
+		}
+		// buffered, so that the attempts never block once the call is over
+		results := make(chan result, p.MaxAttempts)
+		started, pending := 0, 0
+		var hedge <-chan time.Time
+		startAttempt := func() {
+			started++
+			pending++
+			attemptReply := replyMsg.ProtoReflect().New().Interface()
+			go func() {
+				err := invoker(ctx, method, req, attemptReply, cc, opts...)
+				results <- result{reply: attemptReply, err: err}
+			}()
+			hedge = nil
+			if started < p.MaxAttempts {
+				hedge = time.After(p.Delay)
+			}
+		}
+
+		startAttempt()
+		for {
+			select {
+			case <-hedge:
+				startAttempt()
+			case res := <-results:
+				pending--
+				if res.err == nil {
+					proto.Reset(replyMsg)
+					proto.Merge(replyMsg, res.reply)
+					return nil
+				}
+				if !IsRetryable(res.err) {
+					return res.err
+				}
+				if started < p.MaxAttempts && ctx.Err() == nil {
+					startAttempt()
+				} else if pending == 0 {
+					return res.err
+				}
+			}
+		}
+	}
+}
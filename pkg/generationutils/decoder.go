@@ -23,11 +23,21 @@ type BeamSearchDecoder struct {
 	PredictNext PredictNextFunc
 	// SelectNext is a function that selects the next tokens given the current tokens.
 	SelectNext DecodingStrategyFunc
+	// OnStep is an optional function called after each decoding step with
+	// the sequence of the best beam generated so far.
+	OnStep StepFunc
+	// ShouldStop is an optional function called after each decoding step
+	// with the sequence of the best beam. Returning true ends the search.
+	ShouldStop func(bestSequence []int) bool
 }
 
 // PredictNextFunc is a function that predicts the next token scores for a given input.
 type PredictNextFunc func(decodingInputIDs [][]int, lastBeamIndices []int) []mat.Matrix
 
+// StepFunc is a function that receives the sequence of the best beam after
+// each decoding step.
+type StepFunc func(bestSequence []int)
+
 // ScoredToken associates a score to a token identified by its
 // (beam-index, token-index) position.
 type ScoredToken struct {
@@ -63,6 +73,12 @@ Loop:
 		if isDone = hs.isDone(selected[0].Score, curLen); isDone {
 			break
 		}
+		if b.OnStep != nil && len(inputIDs) > 0 {
+			b.OnStep(inputIDs[0])
+		}
+		if b.ShouldStop != nil && len(inputIDs) > 0 && b.ShouldStop(inputIDs[0]) {
+			break
+		}
 
 		select {
 		case <-ctx.Done():
@@ -0,0 +1,191 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package instrument lets the tasks report measurements about the requests
+// they serve, such as the input length and the time spent tokenizing the
+// input and running the model, to an Observer carried by the context.
+//
+// The stages of the processing of a request are also traced as OpenTelemetry
+// spans, children of the span carried by the context.
+//
+// Without an Observer or a span in the context, the functions of this package
+// do nothing.
+package instrument
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Observer receives the measurements reported by the tasks.
+type Observer interface {
+	// ObserveInputTokens receives the number of tokens of an input sequence.
+	ObserveInputTokens(task string, count int)
+	// ObserveTokenization receives the time spent tokenizing an input.
+	ObserveTokenization(task string, d time.Duration)
+	// ObserveForward receives the time spent running the model.
+	ObserveForward(task string, d time.Duration)
+	// ObserveBatch receives the size of a batch of requests run together,
+	// and the time the batch waited for requests to join it.
+	ObserveBatch(task string, size int, wait time.Duration)
+	// ObserveCache receives the outcome of a lookup in a response cache.
+	ObserveCache(task string, hit bool)
+}
+
+// BatchObserver is an Observer of the runs of batches of inputs, telling the
+// inputs apart.
+type BatchObserver interface {
+	Observer
+	// ObserveBatchInputTokens receives the number of tokens of the i-th
+	// input sequence of a batch.
+	ObserveBatchInputTokens(task string, i, count int)
+}
+
+type observerKey struct{}
+
+// WithObserver returns a copy of the context carrying the Observer.
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+// FromContext returns the Observer carried by the context, or nil.
+func FromContext(ctx context.Context) Observer {
+	o, _ := ctx.Value(observerKey{}).(Observer)
+	return o
+}
+
+// observer returns the Observer carried by the context, if any.
+func observer(ctx context.Context) (Observer, bool) {
+	o := FromContext(ctx)
+	return o, o != nil
+}
+
+// Join returns an Observer forwarding the measurements to all the given
+// observers, skipping the nil ones.
+func Join(observers ...Observer) Observer {
+	var joined multiObserver
+	for _, o := range observers {
+		if o != nil {
+			joined = append(joined, o)
+		}
+	}
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	default:
+		return joined
+	}
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) ObserveInputTokens(task string, count int) {
+	for _, o := range m {
+		o.ObserveInputTokens(task, count)
+	}
+}
+
+func (m multiObserver) ObserveTokenization(task string, d time.Duration) {
+	for _, o := range m {
+		o.ObserveTokenization(task, d)
+	}
+}
+
+func (m multiObserver) ObserveForward(task string, d time.Duration) {
+	for _, o := range m {
+		o.ObserveForward(task, d)
+	}
+}
+
+func (m multiObserver) ObserveBatch(task string, size int, wait time.Duration) {
+	for _, o := range m {
+		o.ObserveBatch(task, size, wait)
+	}
+}
+
+func (m multiObserver) ObserveCache(task string, hit bool) {
+	for _, o := range m {
+		o.ObserveCache(task, hit)
+	}
+}
+
+// InputTokens reports the number of tokens of an input sequence.
+func InputTokens(ctx context.Context, task string, count int) {
+	if o, ok := observer(ctx); ok {
+		o.ObserveInputTokens(task, count)
+	}
+}
+
+// BatchInputTokens reports the number of tokens of the i-th input sequence
+// of a batch. It is reported as InputTokens if the Observer is not a
+// BatchObserver.
+func BatchInputTokens(ctx context.Context, task string, i, count int) {
+	o, ok := observer(ctx)
+	if !ok {
+		return
+	}
+	if bo, ok := o.(BatchObserver); ok {
+		bo.ObserveBatchInputTokens(task, i, count)
+		return
+	}
+	o.ObserveInputTokens(task, count)
+}
+
+// WithBatchInputs returns a copy of the context for running a batch made of
+// some of the inputs of the batch of ctx, the i-th input being the
+// indices[i]-th one of the batch of ctx. It returns ctx if its Observer is
+// not a BatchObserver.
+func WithBatchInputs(ctx context.Context, indices []int) context.Context {
+	bo, ok := FromContext(ctx).(BatchObserver)
+	if !ok {
+		return ctx
+	}
+	return WithObserver(ctx, &subBatchObserver{BatchObserver: bo, indices: indices})
+}
+
+// subBatchObserver is the BatchObserver of a batch made of some of the
+// inputs of another one. The input tokens reported without index are taken
+// as those of the inputs in order.
+type subBatchObserver struct {
+	BatchObserver
+	indices []int
+
+	mu   sync.Mutex
+	next int
+}
+
+func (o *subBatchObserver) ObserveInputTokens(task string, count int) {
+	o.mu.Lock()
+	i := o.next
+	o.next++
+	o.mu.Unlock()
+	o.ObserveBatchInputTokens(task, i, count)
+}
+
+func (o *subBatchObserver) ObserveBatchInputTokens(task string, i, count int) {
+	if i >= 0 && i < len(o.indices) {
+		i = o.indices[i]
+	} else {
+		i = -1
+	}
+	o.BatchObserver.ObserveBatchInputTokens(task, i, count)
+}
+
+// Batch reports the size of a batch of requests, and the time it waited
+// for requests to join it since start.
+func Batch(ctx context.Context, task string, size int, start time.Time) {
+	if o, ok := observer(ctx); ok {
+		o.ObserveBatch(task, size, time.Since(start))
+	}
+}
+
+// Cache reports whether a request was served from a response cache.
+func Cache(ctx context.Context, task string, hit bool) {
+	if o, ok := observer(ctx); ok {
+		o.ObserveCache(task, hit)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer creating the spans of the stages.
+const tracerName = "github.com/yinziyang/cybertron/pkg/instrument"
+
+// Stage is a stage of the processing of a request.
+type Stage string
+
+const (
+	// Tokenization is the conversion of the input text to tokens.
+	Tokenization Stage = "tokenization"
+	// Forward is the run of the model on the tokens.
+	Forward Stage = "forward"
+	// Encoding is the run of the encoder of an encoder-decoder model.
+	Encoding Stage = "encoding"
+	// DecodingStep is the prediction of the next tokens of all the beams.
+	DecodingStep Stage = "decoding-step"
+	// Detokenization is the conversion of the output tokens to text.
+	Detokenization Stage = "detokenization"
+)
+
+// Attribute keys of the spans.
+const (
+	TaskKey      = attribute.Key("cybertron.task")
+	ModelKey     = attribute.Key("cybertron.model")
+	TokensKey    = attribute.Key("cybertron.tokens")
+	BatchSizeKey = attribute.Key("cybertron.batch_size")
+	BeamsKey     = attribute.Key("cybertron.beams")
+	StepKey      = attribute.Key("cybertron.decoding_step")
+)
+
+// Span measures a stage of the processing of a request.
+type Span struct {
+	ctx   context.Context
+	task  string
+	stage Stage
+	start time.Time
+	span  trace.Span
+}
+
+// Start starts the stage of the task, returning the context carrying its
+// trace span, to be used for the nested stages, and the Span to end once
+// the stage is done.
+//
+// The span is created with the TracerProvider of the span carried by ctx,
+// so that the stages are traced only as part of a traced request.
+func Start(ctx context.Context, task string, stage Stage, attrs ...attribute.KeyValue) (context.Context, *Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	attrs = append(attrs, TaskKey.String(task))
+	spanCtx, span := tracer.Start(ctx, task+"/"+string(stage), trace.WithAttributes(attrs...))
+	return spanCtx, &Span{
+		ctx:   ctx,
+		task:  task,
+		stage: stage,
+		start: time.Now(),
+		span:  span,
+	}
+}
+
+// End ends the stage, adding the attributes to its trace span. The time
+// spent tokenizing and running the model is also reported to the Observer.
+func (s *Span) End(attrs ...attribute.KeyValue) {
+	if o, ok := observer(s.ctx); ok {
+		switch s.stage {
+		case Tokenization:
+			o.ObserveTokenization(s.task, time.Since(s.start))
+		case Forward:
+			o.ObserveForward(s.task, time.Since(s.start))
+		}
+	}
+	s.span.SetAttributes(attrs...)
+	s.span.End()
+}
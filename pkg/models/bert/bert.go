@@ -50,3 +50,33 @@ func (m *Model) EncodeTokens(tokens []string) []mat.Tensor {
 	// return m.Encoder.Encode(m.Embeddings.EncodeTokens(tokens))
 	return encode
 }
+
+// EncodeTokensBatch produces the encoded representation for a batch of token
+// sequences. The sequences are padded to the length of the longest one, and
+// the padded positions are masked in the self-attention, so that each result
+// matches the one of EncodeTokens for the same sequence.
+func (m *Model) EncodeTokensBatch(batch [][]string) [][]mat.Tensor {
+	if len(batch) == 0 {
+		return nil
+	}
+	maxLength := 0
+	for _, tokens := range batch {
+		maxLength = max(maxLength, len(tokens))
+	}
+	if maxLength == 0 {
+		return make([][]mat.Tensor, len(batch))
+	}
+
+	embeddings := make([][]mat.Tensor, len(batch))
+	masks := make([]mat.Tensor, len(batch))
+	for i, tokens := range batch {
+		embeddings[i] = m.Embeddings.encodeTokensWithPadding(tokens, maxLength)
+		masks[i] = paddingMask(embeddings[i][0], len(tokens), maxLength)
+	}
+
+	encoded := m.Encoder.EncodeBatch(embeddings, masks)
+	for i, tokens := range batch {
+		encoded[i] = encoded[i][:len(tokens)]
+	}
+	return encoded
+}
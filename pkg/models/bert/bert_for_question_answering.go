@@ -46,3 +46,18 @@ func (m *ModelForQuestionAnswering) Answer(tokens []string) (starts, ends []mat.
 	}
 	return
 }
+
+// AnswerBatch returns the "span start logits" and "span end logits" of each
+// sequence in the batch.
+func (m *ModelForQuestionAnswering) AnswerBatch(batch [][]string) (starts, ends [][]mat.Tensor) {
+	encoded := m.Bert.EncodeTokensBatch(batch)
+	starts = make([][]mat.Tensor, len(encoded))
+	ends = make([][]mat.Tensor, len(encoded))
+	for i, lastHiddenStates := range encoded {
+		for _, y := range m.Classifier.Forward(lastHiddenStates...) {
+			starts[i] = append(starts[i], ag.At(y, 0))
+			ends[i] = append(ends[i], ag.At(y, 1))
+		}
+	}
+	return
+}
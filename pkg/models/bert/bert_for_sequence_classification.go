@@ -54,3 +54,14 @@ func (m *ModelForSequenceClassification) Classify(tokens []string) mat.Tensor {
 	return forward[0]
 	// return m.Classifier.Forward(m.Bert.Pooler.Forward(m.Bert.EncodeTokens(tokens)[0]))[0]
 }
+
+// ClassifyBatch returns the logits for the sequence classification of each
+// sequence in the batch.
+func (m *ModelForSequenceClassification) ClassifyBatch(batch [][]string) []mat.Tensor {
+	encoded := m.Bert.EncodeTokensBatch(batch)
+	pooled := make([]mat.Tensor, len(encoded))
+	for i, lastHiddenStates := range encoded {
+		pooled[i] = m.Bert.Pooler.Forward(lastHiddenStates[0])
+	}
+	return m.Classifier.Forward(pooled...)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/nn"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
 )
 
 var _ nn.Model = &ModelForQuestionAnswering{}
@@ -52,6 +53,20 @@ func (m *ModelForSequenceEncoding) Encode(tokens []string, poolingStrategy Pooli
 	return m.pooling(m.Bert.EncodeTokens(tokens), poolingStrategy)
 }
 
+// EncodeBatch returns the vector representations for a batch of input sequences.
+func (m *ModelForSequenceEncoding) EncodeBatch(batch [][]string, poolingStrategy PoolingStrategyType) ([]mat.Tensor, error) {
+	encoded := m.Bert.EncodeTokensBatch(batch)
+	result := make([]mat.Tensor, len(encoded))
+	for i, lastHiddenStates := range encoded {
+		pooled, err := m.pooling(lastHiddenStates, poolingStrategy)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = pooled
+	}
+	return result, nil
+}
+
 func (m *ModelForSequenceEncoding) pooling(lastHiddenStates []mat.Tensor, ps PoolingStrategyType) (mat.Tensor, error) {
 	switch ps {
 	case MeanPooling:
@@ -63,6 +78,6 @@ func (m *ModelForSequenceEncoding) pooling(lastHiddenStates []mat.Tensor, ps Poo
 	case ClsTokenPooling:
 		return m.Bert.Pooler.Forward(lastHiddenStates[0]), nil
 	default:
-		return nil, fmt.Errorf("bert: invalid pooling strategy")
+		return nil, fmt.Errorf("bert: %w %d", textencoding.ErrInvalidPoolingStrategy, ps)
 	}
 }
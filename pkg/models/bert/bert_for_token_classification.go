@@ -40,3 +40,13 @@ func NewModelForTokenClassification[T float.DType](bert *Model) *ModelForTokenCl
 func (m *ModelForTokenClassification) Classify(tokens []string) []mat.Tensor {
 	return m.Classifier.Forward(m.Bert.EncodeTokens(tokens)...)
 }
+
+// ClassifyBatch returns the logits for each token of each sequence in the batch.
+func (m *ModelForTokenClassification) ClassifyBatch(batch [][]string) [][]mat.Tensor {
+	encoded := m.Bert.EncodeTokensBatch(batch)
+	result := make([][]mat.Tensor, len(encoded))
+	for i, lastHiddenStates := range encoded {
+		result[i] = m.Classifier.Forward(lastHiddenStates...)
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bert
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/initializers"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn"
+	emb "github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/vocabulary"
+)
+
+// newTestModel returns a small model with random weights.
+func newTestModel() *Model {
+	vocab := vocabulary.New([]string{"[PAD]", "[UNK]", "[CLS]", "[SEP]", "a", "b", "c", "d"})
+	m := New[float64](Config{
+		HiddenAct:             "gelu",
+		HiddenSize:            8,
+		EmbeddingsSize:        8,
+		IntermediateSize:      16,
+		MaxPositionEmbeddings: 16,
+		NumAttentionHeads:     2,
+		NumHiddenLayers:       2,
+		TypeVocabSize:         2,
+		VocabSize:             len(vocab.Items()),
+	})
+	m.Embeddings.Vocab = vocab
+
+	rng := rand.NewLockedRand(42)
+	initParam := func(p *nn.Param) {
+		initializers.Uniform(p.Value().(mat.Matrix), -0.5, 0.5, rng)
+	}
+	nn.ForEachParam(m, initParam)
+	for _, e := range []*emb.Model{m.Embeddings.Tokens, m.Embeddings.Positions, m.Embeddings.TokenTypes} {
+		for _, p := range e.Weights {
+			initParam(p)
+		}
+	}
+	return m
+}
+
+// assertBatchMatchesSingle asserts that each sequence of the batch is encoded
+// as on its own.
+func assertBatchMatchesSingle(t *testing.T, m *Model, batch [][]string) {
+	t.Helper()
+	batched := m.EncodeTokensBatch(batch)
+	require.Len(t, batched, len(batch))
+
+	for i, tokens := range batch {
+		single := m.EncodeTokens(tokens)
+		require.Len(t, batched[i], len(single))
+		for j := range single {
+			assert.InDeltaSlice(t, single[j].Value().Data().F64(), batched[i][j].Value().Data().F64(), 1e-9,
+				"sequence %d, token %d", i, j)
+		}
+	}
+}
+
+func TestModel_EncodeTokensBatch(t *testing.T) {
+	assertBatchMatchesSingle(t, newTestModel(), [][]string{
+		{"[CLS]", "a", "b", "c", "[SEP]"},
+		{"[CLS]", "d", "[SEP]"},
+		{"[CLS]", "a", "[SEP]", "b", "d", "c", "[SEP]"},
+	})
+}
+
+func TestModel_EncodeTokensBatch_PaddedPairs(t *testing.T) {
+	assertBatchMatchesSingle(t, newTestModel(), [][]string{
+		{"[CLS]", "a", "[SEP]", "b", "[SEP]"},
+		{"[CLS]", "a", "b", "c", "d", "a", "b", "c", "d", "[SEP]"},
+		{"[CLS]", "d", "c", "[SEP]", "a", "b", "[SEP]"},
+	})
+}
+
+func TestModel_EncodeTokensBatch_Empty(t *testing.T) {
+	m := &Model{}
+	assert.Nil(t, m.EncodeTokensBatch(nil))
+}
+
+func TestModelForQuestionAnswering_AnswerBatch(t *testing.T) {
+	m := NewModelForQuestionAnswering[float64](newTestModel())
+	rng := rand.NewLockedRand(42)
+	nn.ForEachParam(m.Classifier, func(p *nn.Param) {
+		initializers.Uniform(p.Value().(mat.Matrix), -0.5, 0.5, rng)
+	})
+
+	batch := [][]string{
+		{"[CLS]", "a", "[SEP]", "b", "c", "d", "[SEP]"},
+		{"[CLS]", "d", "c", "[SEP]", "a", "[SEP]"},
+	}
+	starts, ends := m.AnswerBatch(batch)
+	require.Len(t, starts, len(batch))
+	require.Len(t, ends, len(batch))
+
+	for i, tokens := range batch {
+		singleStarts, singleEnds := m.Answer(tokens)
+		require.Len(t, starts[i], len(singleStarts))
+		require.Len(t, ends[i], len(singleEnds))
+		for j := range singleStarts {
+			assert.InDelta(t, singleStarts[j].Value().Item().F64(), starts[i][j].Value().Item().F64(), 1e-9, "sequence %d, token %d", i, j)
+			assert.InDelta(t, singleEnds[j].Value().Item().F64(), ends[i][j].Value().Item().F64(), 1e-9, "sequence %d, token %d", i, j)
+		}
+	}
+}
@@ -5,8 +5,6 @@
 package bert
 
 import (
-	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
-	"github.com/yinziyang/cybertron/pkg/vocabulary"
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
@@ -14,6 +12,8 @@ import (
 	emb "github.com/nlpodyssey/spago/nn/embedding"
 	"github.com/nlpodyssey/spago/nn/linear"
 	"github.com/nlpodyssey/spago/nn/normalization/layernorm"
+	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
+	"github.com/yinziyang/cybertron/pkg/vocabulary"
 )
 
 // Embeddings implements a Bert input embedding module.
@@ -47,21 +47,47 @@ func NewEmbeddings[T float.DType](c Config) *Embeddings {
 
 // EncodeTokens performs the Bert input encoding.
 func (m *Embeddings) EncodeTokens(tokens []string) []mat.Tensor {
+	return m.encodeTokensWithPadding(tokens, len(tokens))
+}
+
+// encodeTokensWithPadding performs the Bert input encoding, appending
+// padding tokens until the sequence reaches the given length.
+func (m *Embeddings) encodeTokensWithPadding(tokens []string, length int) []mat.Tensor {
 	var (
-		encoded      = m.Tokens.MustEncode(m.tokensToIDs(tokens))
-		positions    = m.Positions.MustEncode(indices(len(tokens)))
-		tokenType, _ = m.TokenTypes.Embedding(0)
+		encoded    = m.Tokens.MustEncode(m.paddedTokensToIDs(tokens, length))
+		positions  = m.Positions.MustEncode(indices(length))
+		tokenTypes = m.TokenTypes.MustEncode(m.tokenTypeIDs(tokens, length))
 	)
+	for i := 0; i < length; i++ {
+		encoded[i] = ag.Sum(encoded[i], positions[i], tokenTypes[i])
+	}
+	return m.useProjection(m.Norm.Forward(encoded...))
+}
 
+// tokenTypeIDs returns the token type of each position, i.e. the index of
+// the sequence it belongs to, each separator ending a sequence. The type
+// never exceeds the last one of the model, and the padding positions have
+// the type of the last token.
+func (m *Embeddings) tokenTypeIDs(tokens []string, length int) []int {
+	IDs := make([]int, length)
 	sequenceIndex := 0
-	for i := 0; i < len(tokens); i++ {
-		encoded[i] = ag.Sum(encoded[i], positions[i], tokenType)
-		if tokens[i] == wordpiecetokenizer.DefaultSequenceSeparator {
+	for i := range IDs {
+		IDs[i] = sequenceIndex
+		if i+1 < len(tokens) && tokens[i] == wordpiecetokenizer.DefaultSequenceSeparator && sequenceIndex+1 < m.Config.TypeVocabSize {
 			sequenceIndex++
-			tokenType, _ = m.TokenTypes.Embedding(sequenceIndex)
 		}
 	}
-	return m.useProjection(m.Norm.Forward(encoded...))
+	return IDs
+}
+
+// paddedTokensToIDs returns the IDs of the given tokens, followed by as many
+// padding token IDs as needed to reach the given length.
+func (m *Embeddings) paddedTokensToIDs(tokens []string, length int) []int {
+	IDs := m.tokensToIDs(tokens)
+	for len(IDs) < length {
+		IDs = append(IDs, m.Config.PadTokenId)
+	}
+	return IDs
 }
 
 // tokensToIDs returns the IDs of the given tokens.
@@ -46,3 +46,12 @@ func (e *Encoder) Encode(xs []mat.Tensor) []mat.Tensor {
 
 	return result
 }
+
+// EncodeBatch performs the Bert encoding of a batch of padded sequences,
+// masking the padded positions with the given attention masks.
+func (e *Encoder) EncodeBatch(xs [][]mat.Tensor, masks []mat.Tensor) [][]mat.Tensor {
+	for _, layer := range e.Layers {
+		xs = layer.ForwardBatch(xs, masks)
+	}
+	return xs
+}
@@ -59,3 +59,21 @@ func (m *EncoderLayer) Forward(xs ...mat.Tensor) []mat.Tensor {
 	log.Println("encoder layer forward end")
 	return ffForward
 }
+
+// ForwardBatch performs the forward step for a batch of padded sequences,
+// masking the padded positions with the given attention masks.
+// The feed-forward block processes the positions of all sequences at once.
+func (m *EncoderLayer) ForwardBatch(xs [][]mat.Tensor, masks []mat.Tensor) [][]mat.Tensor {
+	attended := make([]mat.Tensor, 0, len(xs)*len(xs[0]))
+	for i, x := range xs {
+		attended = append(attended, m.SelfAttention.ForwardWithMask(x, masks[i])...)
+	}
+
+	ff := m.FF.Forward(attended)
+
+	result := make([][]mat.Tensor, len(xs))
+	for i, x := range xs {
+		result[i], ff = ff[:len(x):len(x)], ff[len(x):]
+	}
+	return result
+}
@@ -6,6 +6,7 @@ package bert
 
 import (
 	"encoding/gob"
+	"math"
 
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
@@ -55,3 +56,63 @@ func (m SelfAttentionBlock) Forward(xs []mat.Tensor) []mat.Tensor {
 
 	return m.Norm.Forward(residual...)
 }
+
+// ForwardWithMask works like Forward, but it adds the given mask to the
+// attention scores of every position. The mask has one value for each
+// position: zero for the positions to attend to, and -inf for the (padding)
+// positions to ignore. A nil mask is equivalent to Forward.
+func (m SelfAttentionBlock) ForwardWithMask(xs []mat.Tensor, mask mat.Tensor) []mat.Tensor {
+	if mask == nil {
+		return m.Forward(xs)
+	}
+	att := m.maskedAttention(xs, mask)
+
+	residual := att // reuse the same slice to avoid allocation
+	for i := range residual {
+		residual[i] = ag.Add(xs[i], att[i])
+	}
+
+	return m.Norm.Forward(residual...)
+}
+
+// maskedAttention performs the multi-head scaled dot-product attention,
+// masking the attention scores before the softmax.
+func (m SelfAttentionBlock) maskedAttention(xs []mat.Tensor, mask mat.Tensor) []mat.Tensor {
+	heads := m.Attention.Heads
+	attentions := make([][]mat.Tensor, len(heads))
+	for h, head := range heads {
+		pq := head.Query.Forward(xs...)
+		pk := ag.Stack(head.Key.Forward(xs...)...)
+		pv := ag.Stack(head.Value.Forward(xs...)...)
+
+		attentions[h] = make([]mat.Tensor, len(pq))
+		for i, qi := range pq {
+			scores := ag.Add(ag.ProdScalar(ag.Mul(pk, qi), head.ScaleFactor), mask)
+			attentions[h][i] = ag.MulT(pv, ag.Softmax(scores))
+		}
+	}
+
+	concat := make([]mat.Tensor, len(xs))
+	for i := range concat {
+		buf := make([]mat.Tensor, len(heads))
+		for h := range heads {
+			buf[h] = attentions[h][i]
+		}
+		concat[i] = ag.Concat(buf...)
+	}
+	return m.Attention.OutputMerge.Forward(concat...)
+}
+
+// paddingMask returns the attention mask for a sequence of the given length
+// padded to maxLength, or nil if the sequence is not padded.
+func paddingMask(like mat.Tensor, length, maxLength int) mat.Tensor {
+	if length >= maxLength {
+		return nil
+	}
+	negInf := math.Inf(-1)
+	values := make([]float64, maxLength)
+	for i := length; i < maxLength; i++ {
+		values[i] = negInf
+	}
+	return like.Value().(mat.Matrix).NewMatrix(mat.WithBacking(values))
+}
@@ -0,0 +1,450 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestIDMetadataKey is the gRPC metadata key (and HTTP header) carrying
+// the ID of a request. The ID sent by the client, if any, is used in the
+// access logs, otherwise a random one is generated; either way, it is sent
+// back in the response headers.
+const RequestIDMetadataKey = "x-request-id"
+
+// maxRequestIDLength is the maximum length of the request IDs sent by the
+// clients; longer ones are replaced.
+const maxRequestIDLength = 128
+
+// Redactor removes the sensitive content from a copy of a payload, modifying
+// it in place, before it is logged.
+type Redactor func(proto.Message)
+
+// RedactStrings is a Redactor replacing the strings and bytes of a payload,
+// such as the input texts, with their length.
+func RedactStrings(m proto.Message) {
+	redactStrings(m.ProtoReflect())
+}
+
+func redactStrings(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		redact := func(v protoreflect.Value) (protoreflect.Value, bool) {
+			switch fd.Kind() {
+			case protoreflect.StringKind:
+				return protoreflect.ValueOfString(fmt.Sprintf("[redacted %d chars]", utf8.RuneCountInString(v.String()))), true
+			case protoreflect.BytesKind:
+				return protoreflect.ValueOfBytes(nil), true
+			case protoreflect.MessageKind, protoreflect.GroupKind:
+				redactStrings(v.Message())
+			}
+			return v, false
+		}
+		switch {
+		case fd.IsList():
+			for i, list := 0, v.List(); i < list.Len(); i++ {
+				if r, ok := redact(list.Get(i)); ok {
+					list.Set(i, r)
+				}
+			}
+		case fd.IsMap():
+			fd = fd.MapValue()
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if r, ok := redact(mv); ok {
+					v.Map().Set(k, r)
+				}
+				return true
+			})
+		default:
+			if r, ok := redact(v); ok {
+				m.Set(fd, r)
+			}
+		}
+		return true
+	})
+}
+
+// accessLogger logs an entry for each request served, sampling the ones
+// logged along with their payloads, and captures a sample of the requests
+// to the capture sink, if any.
+type accessLogger struct {
+	// enabled reports whether the entries are logged.
+	enabled           bool
+	payloadSampleRate float64
+	redact            Redactor
+	capture           *captureSink
+}
+
+// newAccessLogger returns the accessLogger of the configuration, or nil if
+// neither the access logs nor the traffic capture are enabled. It must be
+// closed once the server has stopped.
+func newAccessLogger(conf *Config) (*accessLogger, error) {
+	if !conf.AccessLogEnabled && conf.CaptureDir == "" {
+		return nil, nil
+	}
+	redact := conf.PayloadRedactor
+	if redact == nil {
+		redact = RedactStrings
+	}
+	l := &accessLogger{enabled: conf.AccessLogEnabled, payloadSampleRate: conf.PayloadLogSampleRate, redact: redact}
+	if conf.CaptureDir != "" {
+		var err error
+		if l.capture, err = newCaptureSink(conf); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// close closes the capture sink, if any.
+func (l *accessLogger) close() error {
+	if l.capture == nil {
+		return nil
+	}
+	return l.capture.close()
+}
+
+// accessEntry is the access log entry of a request, completed while the
+// request is served. It implements instrument.Observer to count the input
+// tokens.
+type accessEntry struct {
+	logger   *accessLogger
+	id       string
+	protocol string
+	peer     string
+	start    time.Time
+	// logPayloads reports whether the payloads are logged, and captured
+	// whether the request is captured.
+	logPayloads bool
+	captured    bool
+
+	mu       sync.Mutex
+	method   string
+	model    string
+	version  string
+	chars    int
+	tokens   int
+	err      error
+	request  proto.Message
+	response proto.Message
+}
+
+var _ instrument.Observer = &accessEntry{}
+
+type accessEntryKey struct{}
+
+// begin starts the access log entry of a request, returning a copy of the
+// context carrying it, along with a logger with the request ID (see
+// zerolog.Ctx).
+func (l *accessLogger) begin(ctx context.Context, id, protocol, method, peer string) (context.Context, *accessEntry) {
+	e := &accessEntry{
+		logger:      l,
+		id:          id,
+		protocol:    protocol,
+		peer:        peer,
+		start:       time.Now(),
+		logPayloads: l.enabled && l.payloadSampleRate > 0 && rand.Float64() < l.payloadSampleRate,
+		captured:    l.capture != nil && l.capture.sample(),
+		method:      method,
+	}
+	ctx = context.WithValue(ctx, accessEntryKey{}, e)
+	ctx = instrument.WithObserver(ctx, instrument.Join(instrument.FromContext(ctx), e))
+	ctx = log.With().Str("request_id", id).Logger().WithContext(ctx)
+	return ctx, e
+}
+
+// end logs the entry, and captures the request if sampled and successful.
+func (e *accessEntry) end() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	latency := time.Since(e.start)
+	if e.logger.enabled {
+		e.log(latency)
+	}
+	if e.captured && e.err == nil && e.request != nil && e.response != nil {
+		e.logger.capture.write(e, latency)
+	}
+}
+
+// log logs the entry. It must be called with the mutex held.
+func (e *accessEntry) log(latency time.Duration) {
+	code := status.Code(e.err)
+	var event *zerolog.Event
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		event = log.Error()
+	default:
+		event = log.Info()
+	}
+	event = event.
+		Str("request_id", e.id).
+		Str("protocol", e.protocol).
+		Str("method", e.method).
+		Str("peer", e.peer).
+		Dur("latency", latency).
+		Str("code", code.String())
+	if e.model != "" {
+		event = event.Str("model", e.model)
+	}
+	if e.chars > 0 {
+		event = event.Int("input_chars", e.chars)
+	}
+	if e.tokens > 0 {
+		event = event.Int("input_tokens", e.tokens)
+	}
+	if e.err != nil {
+		event = event.Str("error", status.Convert(e.err).Message())
+	}
+	if e.logPayloads {
+		if data := e.payload(e.request); data != nil {
+			event = event.RawJSON("request", data)
+		}
+		if data := e.payload(e.response); data != nil {
+			event = event.RawJSON("response", data)
+		}
+	}
+	event.Msg("request served")
+}
+
+// payload returns the payload redacted and marshaled, or nil if missing.
+func (e *accessEntry) payload(m proto.Message) []byte {
+	if m == nil {
+		return nil
+	}
+	m = proto.Clone(m)
+	e.logger.redact(m)
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		log.Err(err).Str("request_id", e.id).Msg("failed to marshal payload for access log")
+		return nil
+	}
+	return data
+}
+
+// keepsPayloads reports whether the payloads are logged or captured.
+func (e *accessEntry) keepsPayloads() bool {
+	return e.logPayloads || e.captured
+}
+
+func (e *accessEntry) ObserveInputTokens(_ string, count int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tokens += count
+}
+
+func (e *accessEntry) ObserveTokenization(string, time.Duration) {}
+
+func (e *accessEntry) ObserveForward(string, time.Duration) {}
+
+func (e *accessEntry) ObserveBatch(string, int, time.Duration) {}
+
+func (e *accessEntry) ObserveCache(string, bool) {}
+
+// logRequest adds the length of the input texts of a request, and the
+// request itself if its payloads are kept, to the access log entry in the
+// context, if any.
+func logRequest(ctx context.Context, req proto.Message) {
+	e, ok := ctx.Value(accessEntryKey{}).(*accessEntry)
+	if !ok {
+		return
+	}
+	chars := countChars(req.ProtoReflect())
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.chars = chars
+	if e.keepsPayloads() {
+		e.request = req
+	}
+}
+
+// logResponse adds the response, if its payloads are kept, to the access
+// log entry in the context, if any.
+func logResponse(ctx context.Context, resp proto.Message) {
+	if e, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok && e.keepsPayloads() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.response = resp
+	}
+}
+
+// logModel adds the name and version of the model serving the request to
+// the access log entry in the context, if any.
+func logModel(ctx context.Context, name, version string) {
+	if e, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.model, e.version = name, version
+	}
+}
+
+// logError sets the error of the request to the access log entry in the
+// context, if any.
+func logError(ctx context.Context, err error) {
+	if e, ok := ctx.Value(accessEntryKey{}).(*accessEntry); ok {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.err = err
+	}
+}
+
+// countChars returns the number of characters of the strings of a message,
+// except the model name.
+func countChars(m protoreflect.Message) int {
+	n := 0
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Name() == "model" || fd.IsMap() {
+			return true
+		}
+		count := func(v protoreflect.Value) {
+			switch fd.Kind() {
+			case protoreflect.StringKind:
+				n += utf8.RuneCountInString(v.String())
+			case protoreflect.MessageKind:
+				n += countChars(v.Message())
+			}
+		}
+		if fd.IsList() {
+			for i, list := 0, v.List(); i < list.Len(); i++ {
+				count(list.Get(i))
+			}
+		} else {
+			count(v)
+		}
+		return true
+	})
+	return n
+}
+
+// requestID returns the request ID sent by the client, if valid, or a new
+// random one.
+func requestID(sent string) string {
+	if sent != "" && len(sent) <= maxRequestIDLength && isPrintableASCII(sent) {
+		return sent
+	}
+	return newRandomID()
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRandomID returns a new random hexadecimal ID.
+func newRandomID() string {
+	var b [16]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("server: failed to generate random ID: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// unaryServerInterceptor returns a gRPC interceptor logging the unary calls.
+func (l *accessLogger) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, e := l.beginIncoming(ctx, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, e.id))
+		resp, err := handler(ctx, req)
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			logResponse(ctx, msg)
+		}
+		logError(ctx, err)
+		e.end()
+		return resp, err
+	}
+}
+
+// streamServerInterceptor returns a gRPC interceptor logging the streaming
+// calls. The payloads of the responses are not logged.
+func (l *accessLogger) streamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, e := l.beginIncoming(ss.Context(), info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, e.id))
+		err := handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+		logError(ctx, err)
+		e.end()
+		return err
+	}
+}
+
+// beginIncoming starts the access log entry of a gRPC call.
+func (l *accessLogger) beginIncoming(ctx context.Context, method string) (context.Context, *accessEntry) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	return l.begin(ctx, requestID(firstValue(md, RequestIDMetadataKey)), "grpc", method, addr)
+}
+
+// httpMiddleware returns an HTTP handler logging the requests served by the
+// gateway, except for the metrics and the probes. The method is the RPC
+// method, once the gateway has routed the request (see serveMuxOptions),
+// or the path.
+func (l *accessLogger) httpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isInfrastructurePath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		id := requestID(r.Header.Get(RequestIDMetadataKey))
+		w.Header().Set(RequestIDMetadataKey, id)
+		ctx, e := l.begin(r.Context(), id, "http", r.URL.Path, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+		e.end()
+	})
+}
+
+// serveMuxOptions returns the options of the gateway mux completing the
+// access log entry of each request with its RPC method and its response.
+// The error is set by httpErrorHandler.
+func (l *accessLogger) serveMuxOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithMetadata(func(ctx context.Context, _ *http.Request) metadata.MD {
+			e, ok := ctx.Value(accessEntryKey{}).(*accessEntry)
+			method, hasMethod := runtime.RPCMethod(ctx)
+			if ok && hasMethod {
+				e.mu.Lock()
+				e.method = method
+				e.mu.Unlock()
+			}
+			return nil
+		}),
+		runtime.WithForwardResponseOption(func(ctx context.Context, _ http.ResponseWriter, resp proto.Message) error {
+			logResponse(ctx, resp)
+			return nil
+		}),
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// logBuffer is a concurrency-safe buffer of JSON log lines.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries returns the logged entries with the given message.
+func (b *logBuffer) entries(t *testing.T, msg string) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		entry := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["message"] == msg {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// captureLogs redirects the global logger to the returned buffer until the
+// end of the test.
+func captureLogs(t *testing.T) *logBuffer {
+	buf := &logBuffer{}
+	logger := log.Logger
+	log.Logger = zerolog.New(buf)
+	t.Cleanup(func() { log.Logger = logger })
+	return buf
+}
+
+func TestRedactStrings(t *testing.T) {
+	req := &textencodingv1.BatchEncodingRequest{Inputs: []string{"secret", "héllo"}, PoolingStrategy: 2, Model: "embeddings"}
+	RedactStrings(req)
+	assert.Equal(t, []string{"[redacted 6 chars]", "[redacted 5 chars]"}, req.GetInputs())
+	assert.Equal(t, int32(2), req.GetPoolingStrategy())
+	assert.Equal(t, "[redacted 10 chars]", req.GetModel())
+}
+
+func TestNewAccessLogger_Disabled(t *testing.T) {
+	al, err := newAccessLogger(&Config{})
+	require.NoError(t, err)
+	assert.Nil(t, al, "disabled by default")
+}
+
+func TestServer_AccessLog(t *testing.T) {
+	logs := captureLogs(t)
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+	s := startServer(t, &Config{AccessLogEnabled: true, PayloadLogSampleRate: 1}, r)
+	ctx := context.Background()
+
+	t.Run("gRPC", func(t *testing.T) {
+		conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var header metadata.MD
+		ctx := metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, "req-1")
+		_, err = textencodingv1.NewTextEncodingServiceClient(conn).Encode(ctx,
+			&textencodingv1.EncodingRequest{Input: "top secret"}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-1"}, header.Get(RequestIDMetadataKey), "the request ID is echoed")
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		resp, err := http.Post("http://"+s.ClientAddr()+"/v1/encode", "application/json", strings.NewReader(`{"input": "top secret"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, resp.Header.Get(RequestIDMetadataKey), 32, "a request ID is generated")
+
+		resp, err = http.Get("http://" + s.ClientAddr() + "/livez")
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	entries := logs.entries(t, "request served")
+	require.Len(t, entries, 2, "the probes are not logged")
+	for i, protocol := range []string{"grpc", "http"} {
+		entry := entries[i]
+		assert.Equal(t, protocol, entry["protocol"])
+		assert.Equal(t, "/textencoding.v1.TextEncodingService/Encode", entry["method"])
+		assert.Equal(t, "embeddings", entry["model"])
+		assert.Equal(t, "OK", entry["code"])
+		assert.Equal(t, float64(10), entry["input_chars"])
+		assert.Equal(t, float64(2), entry["input_tokens"])
+		assert.Contains(t, entry["peer"], "127.0.0.1:")
+		assert.Contains(t, entry, "latency")
+		assert.Equal(t, map[string]any{"input": "[redacted 10 chars]"}, entry["request"])
+		assert.Equal(t, map[string]any{"vector": []any{float64(1), float64(2)}}, entry["response"])
+	}
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.NotEmpty(t, entries[1]["request_id"])
+}
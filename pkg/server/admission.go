@@ -0,0 +1,230 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// retryAfter is the delay suggested to the clients before retrying the
+// requests rejected because the server is overloaded.
+const retryAfter = time.Second
+
+// admission enforces the limits of the configuration on the inference
+// requests: the size of the inputs, the number of concurrent inferences and
+// the length of the queue of the requests waiting for their turn, served by
+// priority (see scheduler).
+//
+// The admission is carried by the context of the requests, and applied by
+// the modelResolver once the model of the request is known.
+type admission struct {
+	maxInputChars      int
+	maxCandidateLabels int
+	maxBatchInputs     int
+	// conf is the configuration of the limits, applied to the requests
+	// mirrored by the shadow deployments as well (see shadowContext).
+	conf *Config
+	// scheduler grants the inference slots. It is nil if the concurrent
+	// inferences are not limited.
+	scheduler *scheduler
+}
+
+func newAdmission(conf *Config) *admission {
+	a := &admission{
+		maxInputChars:      conf.MaxInputChars,
+		maxCandidateLabels: conf.MaxCandidateLabels,
+		maxBatchInputs:     conf.MaxBatchInputs,
+		conf:               conf,
+	}
+	if conf.MaxConcurrentInferences > 0 {
+		a.scheduler = newScheduler(conf)
+	}
+	return a
+}
+
+type admissionKey struct{}
+
+// admit validates the request and waits for an inference slot, according to
+// the admission carried by the context, if any. It returns the function to
+// call once the request has been served. The request is added to the
+// access log entry of the context, if any.
+func admit(ctx context.Context, req proto.Message) (func(), error) {
+	logRequest(ctx, req)
+	a, ok := ctx.Value(admissionKey{}).(*admission)
+	if !ok {
+		return func() {}, nil
+	}
+	if err := a.validate(req); err != nil {
+		return nil, err
+	}
+	return a.acquire(ctx)
+}
+
+// validate returns an InvalidArgument error if a text of the request is longer
+// than maxInputChars, if it has more than maxCandidateLabels labels, or if it
+// is a batch request with more than maxBatchInputs inputs. The reason of the
+// error is the one of the first violation found.
+func (a *admission) validate(req proto.Message) error {
+	var (
+		reason     string
+		violations []*errdetails.BadRequest_FieldViolation
+	)
+	violate := func(r, field, description string) {
+		if reason == "" {
+			reason = r
+		}
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+	}
+
+	var check func(m protoreflect.Message, prefix string)
+	check = func(m protoreflect.Message, prefix string) {
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			name := prefix + string(fd.Name())
+			if name == "model" || fd.IsMap() {
+				return true
+			}
+			if fd.IsList() && name == "inputs" && a.maxBatchInputs > 0 && v.List().Len() > a.maxBatchInputs {
+				violate(ReasonTooManyInputs, name,
+					fmt.Sprintf("too many inputs: %d > %d", v.List().Len(), a.maxBatchInputs))
+			}
+			if fd.IsList() && fd.Name() == "candidate_labels" && a.maxCandidateLabels > 0 && v.List().Len() > a.maxCandidateLabels {
+				violate(ReasonTooManyCandidateLabels, name,
+					fmt.Sprintf("too many candidate labels: %d > %d", v.List().Len(), a.maxCandidateLabels))
+			}
+			checkValue := func(name string, v protoreflect.Value) {
+				switch fd.Kind() {
+				case protoreflect.StringKind:
+					if n := utf8.RuneCountInString(v.String()); a.maxInputChars > 0 && n > a.maxInputChars {
+						violate(ReasonTooManyCharacters, name,
+							fmt.Sprintf("too many characters: %d > %d", n, a.maxInputChars))
+					}
+				case protoreflect.MessageKind:
+					check(v.Message(), name+".")
+				}
+			}
+			if fd.IsList() {
+				for i, list := 0, v.List(); i < list.Len(); i++ {
+					checkValue(fmt.Sprintf("%s[%d]", name, i), list.Get(i))
+				}
+			} else {
+				checkValue(name, v)
+			}
+			return true
+		})
+	}
+	check(req.ProtoReflect(), "")
+
+	if len(violations) == 0 {
+		return nil
+	}
+	descriptions := make([]string, len(violations))
+	for i, v := range violations {
+		descriptions[i] = v.GetField() + ": " + v.GetDescription()
+	}
+	return withDetails(status.New(codes.InvalidArgument, strings.Join(descriptions, "; ")),
+		&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+}
+
+// acquire waits for an inference slot, according to the priority of the
+// request and the weight of its client, if authenticated. It returns the
+// function to call once the request has been served.
+//
+// It fails with InvalidArgument if the priority is unknown, and with
+// ResourceExhausted if the queue is full or if the request waited in queue
+// longer than maxQueueTime.
+func (a *admission) acquire(ctx context.Context) (func(), error) {
+	priority, err := priorityFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if a.scheduler == nil {
+		return func() {}, nil
+	}
+	tenant, weight := "", 1.0
+	if c, ok := ctx.Value(apiClientKey{}).(*apiClient); ok {
+		tenant, weight = c.name, c.weight
+	}
+	return a.scheduler.acquire(ctx, priority, tenant, weight)
+}
+
+// resourceExhausted returns a ResourceExhausted error, suggesting the clients
+// to retry after a while.
+func resourceExhausted(reason, msg string) error {
+	return withDetails(status.New(codes.ResourceExhausted, msg),
+		&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+}
+
+// limitedRequestContext returns the context of a request carrying the
+// admission and the input limits, with the configured timeout.
+func limitedRequestContext(ctx context.Context, a *admission, conf *Config) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, admissionKey{}, a)
+	ctx = inputlimit.WithMaxTokens(ctx, conf.MaxInputTokens)
+	if conf.RequestTimeout > 0 {
+		return context.WithTimeout(ctx, conf.RequestTimeout)
+	}
+	return ctx, func() {}
+}
+
+// isInfrastructureMethod reports whether the gRPC method belongs to a
+// service provided by gRPC itself, such as the health service, whose calls
+// are not subject to the limits.
+func isInfrastructureMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.")
+}
+
+// admissionUnaryServerInterceptor returns a gRPC interceptor applying the
+// limits to unary calls.
+func admissionUnaryServerInterceptor(a *admission, conf *Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, cancel := limitedRequestContext(ctx, a, conf)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// admissionStreamServerInterceptor returns a gRPC interceptor applying the
+// limits to streaming calls.
+func admissionStreamServerInterceptor(a *admission, conf *Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, cancel := limitedRequestContext(ss.Context(), a, conf)
+		defer cancel()
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// admissionMiddleware returns an HTTP handler applying the limits to the
+// requests served by the gateway.
+func admissionMiddleware(a *admission, conf *Config, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := limitedRequestContext(r.Context(), a, conf)
+		defer cancel()
+		ctx = withPriorityHeader(ctx, r.Header.Get(PriorityMetadataKey))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
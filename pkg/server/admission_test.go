@@ -0,0 +1,142 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	zeroshotv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/zeroshot/v1"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdmission_Validate(t *testing.T) {
+	a := newAdmission(&Config{MaxInputChars: 5, MaxCandidateLabels: 2, MaxBatchInputs: 2})
+
+	assert.NoError(t, a.validate(&zeroshotv1.ClassifyRequest{
+		Input:      "héllo",
+		Parameters: &zeroshotv1.ZeroShotParameters{CandidateLabels: []string{"a", "b"}},
+		Model:      "a-model-with-a-long-name",
+	}))
+
+	t.Run("too many characters", func(t *testing.T) {
+		err := a.validate(&zeroshotv1.ClassifyRequest{Input: "hello world"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		assert.Equal(t, ReasonTooManyCharacters, details[0].(*errdetails.ErrorInfo).GetReason())
+		violations := details[1].(*errdetails.BadRequest).GetFieldViolations()
+		require.Len(t, violations, 1)
+		assert.Equal(t, "input", violations[0].GetField())
+	})
+
+	t.Run("too many candidate labels", func(t *testing.T) {
+		err := a.validate(&zeroshotv1.ClassifyRequest{
+			Parameters: &zeroshotv1.ZeroShotParameters{CandidateLabels: []string{"a", "b", "long label"}},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		assert.Equal(t, ReasonTooManyCandidateLabels, details[0].(*errdetails.ErrorInfo).GetReason())
+		var fields []string
+		for _, v := range details[1].(*errdetails.BadRequest).GetFieldViolations() {
+			fields = append(fields, v.GetField())
+		}
+		assert.Equal(t, []string{"parameters.candidate_labels", "parameters.candidate_labels[2]"}, fields)
+	})
+
+	t.Run("too many inputs", func(t *testing.T) {
+		assert.NoError(t, a.validate(&textencodingv1.BatchEncodingRequest{Inputs: []string{"a", "b"}}))
+
+		err := a.validate(&textencodingv1.BatchEncodingRequest{Inputs: []string{"a", "b", "c"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		assert.Equal(t, ReasonTooManyInputs, details[0].(*errdetails.ErrorInfo).GetReason())
+		violations := details[1].(*errdetails.BadRequest).GetFieldViolations()
+		require.Len(t, violations, 1)
+		assert.Equal(t, "inputs", violations[0].GetField())
+	})
+}
+
+func TestAdmission_Acquire(t *testing.T) {
+	a := newAdmission(&Config{MaxConcurrentInferences: 1, MaxQueueLength: 1, MaxQueueTime: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	release, err := a.acquire(ctx)
+	require.NoError(t, err)
+
+	t.Run("queue timeout", func(t *testing.T) {
+		_, err := a.acquire(ctx)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		assert.Equal(t, ReasonQueueTimeout, details[0].(*errdetails.ErrorInfo).GetReason())
+		assert.Equal(t, retryAfter, details[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		queued := make(chan error)
+		go func() {
+			release, err := a.acquire(ctx)
+			if err == nil {
+				release()
+			}
+			queued <- err
+		}()
+		require.Eventually(t, func() bool { return a.scheduler.queued() == 1 }, time.Second, time.Millisecond)
+
+		_, err := a.acquire(ctx)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, ReasonQueueFull, status.Convert(err).Details()[0].(*errdetails.ErrorInfo).GetReason())
+
+		release()
+		assert.NoError(t, <-queued, "the queued request is served once the slot is released")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		release, err := a.acquire(ctx)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = a.acquire(ctx)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	})
+}
+
+func TestLimitedRequestContext(t *testing.T) {
+	conf := &Config{MaxInputTokens: 128, RequestTimeout: time.Minute}
+	ctx, cancel := limitedRequestContext(context.Background(), newAdmission(conf), conf)
+	defer cancel()
+
+	assert.Equal(t, 128, inputlimit.MaxTokens(ctx, 512))
+	assert.Equal(t, 64, inputlimit.MaxTokens(ctx, 64))
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+}
+
+func TestServer_HTTPAdmission(t *testing.T) {
+	s := startServer(t, &Config{MaxInputChars: 5}, NewServerForTextEncoding(fakeEncoder{}))
+
+	resp, err := http.Post("http://"+s.ClientAddr()+"/v1/encode", "application/json", strings.NewReader(`{"input": "hello world"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,328 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// APIKeyMetadataKey is the gRPC metadata key (and HTTP header) carrying
+	// the API key of a request, as an alternative to a bearer token in the
+	// "authorization" metadata.
+	APIKeyMetadataKey = "x-api-key"
+	// DefaultQuotaPeriod is the default period of the token quotas.
+	DefaultQuotaPeriod = 24 * time.Hour
+)
+
+// APIKey is a key, or static bearer token, granting access to the server,
+// with the limits of the client using it.
+type APIKey struct {
+	// Name identifies the client; unlike the key, it is not secret.
+	Name string `json:"name"`
+	// Key is the secret sent by the client.
+	Key string `json:"key"`
+	// RateLimit is the maximum number of requests per second, with bursts
+	// of up to RateBurst requests. No limit if <= 0.
+	RateLimit float64 `json:"rate_limit"`
+	RateBurst int     `json:"rate_burst"`
+	// TokenQuota is the maximum number of input tokens processed for the
+	// client within each quota period. No limit if <= 0.
+	TokenQuota int64 `json:"token_quota"`
+	// Weight is the share of the inference slots of the client, relative to
+	// the other clients waiting for them (default 1).
+	Weight float64 `json:"weight"`
+	// Admin grants access to the model management API (see
+	// NewServerForModelAdmin), denied to the other clients.
+	Admin bool `json:"admin"`
+}
+
+// LoadAPIKeys reads the API keys from a JSON file containing an array of
+// APIKey objects.
+func LoadAPIKeys(filename string) ([]APIKey, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file %q: %w", filename, err)
+	}
+	return keys, nil
+}
+
+// auth authenticates the requests by API key, and enforces the rate limit
+// and the token quota of each client.
+type auth struct {
+	// clients are indexed by the hash of their key, so that looking up a key
+	// does not leak its content through timing.
+	clients map[[sha256.Size]byte]*apiClient
+}
+
+// apiClient is the state of the limits of an API key.
+type apiClient struct {
+	name    string
+	weight  float64
+	admin   bool
+	limiter *rate.Limiter
+	quota   *tokenQuota
+}
+
+type apiClientKey struct{}
+
+func newAuth(conf *Config) (*auth, error) {
+	period := conf.QuotaPeriod
+	if period <= 0 {
+		period = DefaultQuotaPeriod
+	}
+	a := &auth{clients: make(map[[sha256.Size]byte]*apiClient, len(conf.APIKeys))}
+	for _, k := range conf.APIKeys {
+		if k.Key == "" {
+			return nil, fmt.Errorf("API key %q is empty", k.Name)
+		}
+		hash := sha256.Sum256([]byte(k.Key))
+		if _, exists := a.clients[hash]; exists {
+			return nil, fmt.Errorf("API key %q is duplicated", k.Name)
+		}
+		c := &apiClient{name: k.Name, weight: k.Weight, admin: k.Admin}
+		if c.weight <= 0 {
+			c.weight = 1
+		}
+		if k.RateLimit > 0 {
+			burst := k.RateBurst
+			if burst <= 0 {
+				burst = int(math.Ceil(k.RateLimit))
+			}
+			c.limiter = rate.NewLimiter(rate.Limit(k.RateLimit), burst)
+		}
+		if k.TokenQuota > 0 {
+			c.quota = &tokenQuota{limit: k.TokenQuota, period: period}
+		}
+		a.clients[hash] = c
+	}
+	return a, nil
+}
+
+// authenticate finds the client of the given credentials, and checks that
+// it is within its limits.
+func (a *auth) authenticate(apiKey, authorization string) (*apiClient, error) {
+	key := apiKey
+	if key == "" {
+		scheme, token, ok := strings.Cut(authorization, " ")
+		if !ok || !strings.EqualFold(scheme, "bearer") {
+			return nil, status.Error(codes.Unauthenticated, "missing API key or bearer token")
+		}
+		key = strings.TrimSpace(token)
+	}
+	c, ok := a.clients[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key or bearer token")
+	}
+
+	if c.limiter != nil {
+		r := c.limiter.Reserve()
+		if delay := r.Delay(); delay > 0 {
+			r.Cancel()
+			return nil, withDetails(status.New(codes.ResourceExhausted, "rate limit exceeded"),
+				&errdetails.ErrorInfo{Reason: ReasonRateLimited, Domain: ErrorDomain},
+				&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)},
+			)
+		}
+	}
+	if c.quota != nil {
+		if err := c.quota.check(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// authorize returns a PermissionDenied error if the client is not allowed to
+// call the model management API, when admin is true.
+func (c *apiClient) authorize(admin bool) error {
+	if admin && !c.admin {
+		return status.Errorf(codes.PermissionDenied, "API key %q is not allowed to manage the models", c.name)
+	}
+	return nil
+}
+
+// isAdminMethod reports whether the gRPC method belongs to the model
+// management API.
+func isAdminMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/modeladmin.v1.")
+}
+
+// isAdminPath reports whether the HTTP path is routed to the model
+// management API.
+func isAdminPath(path string) bool {
+	return path == "/v1/models" || strings.HasPrefix(path, "/v1/models/")
+}
+
+// withClient returns a copy of the context carrying the client, and charging
+// the input tokens of the request to its quota.
+func (c *apiClient) withClient(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, apiClientKey{}, c)
+	if c.quota == nil {
+		return ctx
+	}
+	return instrument.WithObserver(ctx, instrument.Join(instrument.FromContext(ctx), c.quota))
+}
+
+// clientName returns the name of the client authenticated for the request
+// of the context, or "" without authentication.
+func clientName(ctx context.Context) string {
+	if c, ok := ctx.Value(apiClientKey{}).(*apiClient); ok {
+		return c.name
+	}
+	return ""
+}
+
+// tokenQuota is the number of input tokens a client can use within a period.
+// The periods are consecutive fixed windows, the first starting with the
+// first request of the client.
+type tokenQuota struct {
+	limit  int64
+	period time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	used  int64
+}
+
+// reset starts a new period if the current one is over. The caller must hold mu.
+func (q *tokenQuota) reset(now time.Time) {
+	if now.Sub(q.start) >= q.period {
+		q.start = now
+		q.used = 0
+	}
+}
+
+// check returns a ResourceExhausted error if the quota is used up.
+func (q *tokenQuota) check() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.reset(now)
+	if q.used < q.limit {
+		return nil
+	}
+	return withDetails(status.New(codes.ResourceExhausted, "input tokens quota exceeded"),
+		&errdetails.ErrorInfo{Reason: ReasonTokenQuotaExceeded, Domain: ErrorDomain},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     "input_tokens",
+			Description: fmt.Sprintf("used %d of %d input tokens per %s", q.used, q.limit, q.period),
+		}}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(q.start.Add(q.period).Sub(now))},
+	)
+}
+
+// ObserveInputTokens charges the tokens of an input to the quota.
+func (q *tokenQuota) ObserveInputTokens(_ string, count int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reset(time.Now())
+	q.used += int64(count)
+}
+
+func (q *tokenQuota) ObserveTokenization(string, time.Duration) {}
+
+func (q *tokenQuota) ObserveForward(string, time.Duration) {}
+
+func (q *tokenQuota) ObserveBatch(string, int, time.Duration) {}
+
+func (q *tokenQuota) ObserveCache(string, bool) {}
+
+// authenticateIncoming authenticates a gRPC call from its metadata, and
+// checks that the client is allowed to call the method.
+func (a *auth) authenticateIncoming(ctx context.Context, fullMethod string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c, err := a.authenticate(firstValue(md, APIKeyMetadataKey), firstValue(md, "authorization"))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.authorize(isAdminMethod(fullMethod)); err != nil {
+		return nil, err
+	}
+	return c.withClient(ctx), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// authUnaryServerInterceptor returns a gRPC interceptor authenticating the
+// unary calls.
+func authUnaryServerInterceptor(a *auth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authenticateIncoming(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamServerInterceptor returns a gRPC interceptor authenticating the
+// streaming calls.
+func authStreamServerInterceptor(a *auth) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isInfrastructureMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := a.authenticateIncoming(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authMiddleware returns an HTTP handler authenticating the requests served
+// by the gateway, except for the metrics and the probes, and allowing only
+// the admin clients to manage the models. The errors are
+// rendered by the error handler of the mux.
+func authMiddleware(a *auth, mux *runtime.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isInfrastructurePath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		c, err := a.authenticate(r.Header.Get(APIKeyMetadataKey), r.Header.Get("Authorization"))
+		if err == nil {
+			err = c.authorize(isAdminPath(r.URL.Path))
+		}
+		if err != nil {
+			writeHTTPError(mux, w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(c.withClient(r.Context())))
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/client"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	modeladminv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/modeladmin/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuth_Authenticate(t *testing.T) {
+	a, err := newAuth(&Config{APIKeys: []APIKey{
+		{Name: "alice", Key: "secret-a"},
+		{Name: "bob", Key: "secret-b", RateLimit: 1, RateBurst: 2},
+		{Name: "carol", Key: "secret-c", TokenQuota: 10},
+	}})
+	require.NoError(t, err)
+
+	t.Run("credentials", func(t *testing.T) {
+		_, err := a.authenticate("secret-a", "")
+		assert.NoError(t, err)
+		_, err = a.authenticate("", "Bearer secret-a")
+		assert.NoError(t, err)
+		_, err = a.authenticate("", "")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = a.authenticate("", "Basic secret-a")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = a.authenticate("wrong", "")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := a.authenticate("secret-b", "")
+			require.NoError(t, err, "within the burst")
+		}
+		_, err := a.authenticate("secret-b", "")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		assert.Equal(t, ReasonRateLimited, details[0].(*errdetails.ErrorInfo).GetReason())
+		assert.Positive(t, details[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+
+		_, err = a.authenticate("secret-a", "")
+		assert.NoError(t, err, "the other clients are not limited")
+	})
+
+	t.Run("token quota", func(t *testing.T) {
+		c, err := a.authenticate("secret-c", "")
+		require.NoError(t, err)
+		instrument.InputTokens(c.withClient(context.Background()), "test", 10)
+
+		_, err = a.authenticate("secret-c", "")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		details := status.Convert(err).Details()
+		require.Len(t, details, 3)
+		assert.Equal(t, ReasonTokenQuotaExceeded, details[0].(*errdetails.ErrorInfo).GetReason())
+		assert.InDelta(t, DefaultQuotaPeriod, details[2].(*errdetails.RetryInfo).GetRetryDelay().AsDuration(), float64(time.Minute))
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := newAuth(&Config{APIKeys: []APIKey{{Name: "empty"}}})
+		assert.Error(t, err)
+		_, err = newAuth(&Config{APIKeys: []APIKey{{Key: "k"}, {Key: "k"}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := startServer(t, &Config{APIKeys: []APIKey{{Name: "test", Key: "secret"}}}, NewServerForTextEncoding(instrumentedEncoder{}))
+	ctx := context.Background()
+
+	t.Run("gRPC", func(t *testing.T) {
+		_, err := client.NewClientForTextEncoding(s.ClientAddr(), client.Options{}).Encode(ctx, "a", 0)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = client.NewClientForTextEncoding(s.ClientAddr(), client.Options{APIKey: "secret"}).Encode(ctx, "a", 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		post := func(header, value string) *http.Response {
+			req, err := http.NewRequest(http.MethodPost, "http://"+s.ClientAddr()+"/v1/encode", strings.NewReader(`{"input": "a"}`))
+			require.NoError(t, err)
+			if header != "" {
+				req.Header.Set(header, value)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp
+		}
+
+		resp := post("", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+		assert.Equal(t, http.StatusOK, post("Authorization", "Bearer secret").StatusCode)
+		assert.Equal(t, http.StatusOK, post("X-Api-Key", "secret").StatusCode)
+	})
+}
+
+func TestServer_AuthAdmin(t *testing.T) {
+	s := startServer(t, &Config{APIKeys: []APIKey{
+		{Name: "user", Key: "secret-user"},
+		{Name: "admin", Key: "secret-admin", Admin: true},
+	}}, JoinRequestHandlers(
+		NewServerForTextEncoding(instrumentedEncoder{}),
+		NewServerForModelAdmin(NewRegistry(), nil),
+	))
+
+	t.Run("gRPC", func(t *testing.T) {
+		conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		admin := modeladminv1.NewModelAdminServiceClient(conn)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadataKey, "secret-user")
+		_, err = admin.ListModels(ctx, &modeladminv1.ListModelsRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		ctx = metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadataKey, "secret-admin")
+		_, err = admin.ListModels(ctx, &modeladminv1.ListModelsRequest{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		do := func(method, path, key string) int {
+			req, err := http.NewRequest(method, "http://"+s.ClientAddr()+path, strings.NewReader(`{"input": "a"}`))
+			require.NoError(t, err)
+			req.Header.Set(APIKeyMetadataKey, key)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "/v1/models", "secret-user"))
+		assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/v1/models/a", "secret-user"))
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/models", "secret-admin"))
+		assert.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/encode", "secret-user"), "the tasks are open to any client")
+	})
+}
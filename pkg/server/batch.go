@@ -0,0 +1,51 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+)
+
+// serveBatch serves the inputs of a batch request, returning the result or
+// the error of each input, in the same order.
+//
+// If batch is not nil, the inputs are first served together; if that fails,
+// they are served one at a time with single, so that each failure is
+// reported on the input causing it. serveBatch fails only if the context is
+// done.
+func serveBatch[In, Out any](ctx context.Context, inputs []In, batch func(context.Context, []In) ([]Out, error), single func(context.Context, In) (Out, error)) ([]Out, []error, error) {
+	if batch != nil && len(inputs) > 0 {
+		// the inputs of a failed batch are served again by single, so the
+		// tokens of the batch are reported only if it succeeds
+		o := &inputsObserver{leader: instrument.FromContext(ctx), inputs: make([]instrument.Observer, len(inputs))}
+		for i := range o.inputs {
+			o.inputs[i] = o.leader
+		}
+		if results, err := batch(instrument.WithObserver(ctx, o), inputs); err == nil {
+			o.commit()
+			return results, make([]error, len(inputs)), nil
+		}
+	}
+
+	results := make([]Out, len(inputs))
+	errs := make([]error, len(inputs))
+	for i, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		results[i], errs[i] = single(ctx, input)
+	}
+	return results, errs, nil
+}
+
+// batchItemError returns the google.rpc.Status reporting the failure of an
+// input of a batch request.
+func batchItemError(err error) *spb.Status {
+	return status.Convert(statusError(err)).Proto()
+}
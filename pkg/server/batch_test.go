@@ -0,0 +1,170 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/client"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServeBatch(t *testing.T) {
+	ctx := context.Background()
+	double := func(ctx context.Context, n int) (int, error) {
+		instrument.InputTokens(ctx, "test", 1)
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return n * 2, nil
+	}
+	doubleAll := func(ctx context.Context, ns []int) ([]int, error) {
+		out := make([]int, len(ns))
+		for i, n := range ns {
+			var err error
+			if out[i], err = double(ctx, n); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		results, errs, err := serveBatch(ctx, []int{1, 2}, doubleAll, double)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 4}, results)
+		assert.Equal(t, []error{nil, nil}, errs)
+	})
+	t.Run("fallback to single inputs", func(t *testing.T) {
+		results, errs, err := serveBatch(ctx, []int{1, -1, 3}, doubleAll, double)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 0, 6}, results)
+		assert.NoError(t, errs[0])
+		assert.EqualError(t, errs[1], "negative")
+		assert.NoError(t, errs[2])
+	})
+	t.Run("tokens of a failed batch", func(t *testing.T) {
+		quota := &tokenQuota{limit: 100, period: time.Hour}
+		_, _, err := serveBatch(instrument.WithObserver(ctx, quota), []int{1, -1, 3}, doubleAll, double)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), quota.used, "only the single inputs are charged")
+	})
+	t.Run("single inputs only", func(t *testing.T) {
+		results, _, err := serveBatch(ctx, []int{1, 2}, nil, double)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 4}, results)
+	})
+	t.Run("context done", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, _, err := serveBatch(canceled, []int{-1}, doubleAll, double)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// partialEncoder fails to encode the text "long", as too long.
+type partialEncoder struct {
+	instrumentedEncoder
+}
+
+func (e partialEncoder) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	if text == "long" {
+		return textencoding.Response{}, taskerrors.InputTooLong(textencoding.ErrInputSequenceTooLong, 600, 512)
+	}
+	return e.instrumentedEncoder.Encode(ctx, text, poolingStrategy)
+}
+
+func (e partialEncoder) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	responses := make([]textencoding.Response, len(texts))
+	for i, text := range texts {
+		var err error
+		if responses[i], err = e.Encode(ctx, text, poolingStrategy); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+func TestServer_Batch(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", partialEncoder{}))
+	require.NoError(t, r.Add("sentiment", fakeClassifier{label: "positive"}))
+	s := startServer(t, &Config{}, r)
+	ctx := context.Background()
+	baseURL := "http://" + s.ClientAddr()
+
+	t.Run("gRPC", func(t *testing.T) {
+		c, err := client.New(ctx, s.ClientAddr(), client.Options{Timeout: 5 * time.Second})
+		require.NoError(t, err)
+		encoder := c.TextEncoding()
+		results, err := encoder.BatchEncode(ctx, []string{"a", "long", "b"}, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, []float32{1, 2}, results[0].Response.Vector.Data().F32())
+		assert.Equal(t, codes.InvalidArgument, status.Code(results[1].Err))
+		info, ok := client.ErrorInfo(results[1].Err)
+		require.True(t, ok)
+		assert.Equal(t, ReasonInputSequenceTooLong, info.GetReason())
+		assert.NoError(t, results[2].Err)
+
+		_, err = encoder.EncodeBatch(ctx, []string{"a", "long"}, 0)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "the first failed input fails the whole call")
+
+		require.NoError(t, c.Close())
+		_, err = encoder.Encode(ctx, "a", 0)
+		assert.ErrorIs(t, err, client.ErrClosed)
+	})
+
+	post := func(path, body string, out any) {
+		resp, err := http.Post(baseURL+path, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	t.Run("REST", func(t *testing.T) {
+		var body struct {
+			Results []struct {
+				Response *struct{ Vector []float32 }
+				Error    *struct {
+					Code    int
+					Message string
+				}
+			}
+		}
+		post("/v1/encode:batch", `{"inputs": ["a", "long"]}`, &body)
+		require.Len(t, body.Results, 2)
+		require.NotNil(t, body.Results[0].Response)
+		assert.Equal(t, []float32{1, 2}, body.Results[0].Response.Vector)
+		require.NotNil(t, body.Results[1].Error)
+		assert.Equal(t, int(codes.InvalidArgument), body.Results[1].Error.Code)
+		assert.Equal(t, "input sequence too long: 600 > 512", body.Results[1].Error.Message)
+	})
+
+	t.Run("REST shared classify route", func(t *testing.T) {
+		var body struct {
+			Results []struct {
+				Response struct{ Labels []string }
+			}
+		}
+		post("/v1/classify:batch", `{"inputs": ["a", "b"], "model": "sentiment"}`, &body)
+		require.Len(t, body.Results, 2)
+		assert.Equal(t, []string{"positive"}, body.Results[1].Response.Labels)
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yinziyang/cybertron/pkg/instrument"
+)
+
+// batcher gathers the inputs of concurrent calls sharing the same key and
+// processes them together with a single invocation of run.
+//
+// The first call of a new batch waits until either maxSize inputs have been
+// collected or maxWait has elapsed, then runs the whole batch on behalf of
+// all the callers. No background goroutine is involved, so a batcher does
+// not need to be started or stopped.
+type batcher[K comparable, In, Out any] struct {
+	// task is the name of the task reported to the instrument.Observer.
+	task    string
+	maxSize int
+	maxWait time.Duration
+	// run processes a batch of inputs, returning one output for each input.
+	run func(ctx context.Context, key K, inputs []In) ([]Out, error)
+	// single processes one input on its own. It is used to find out the
+	// error of each caller when the processing of a batch fails.
+	single func(ctx context.Context, key K, input In) (Out, error)
+
+	mu      sync.Mutex
+	pending map[K]*batch[In, Out]
+}
+
+// batch is a set of inputs processed together.
+type batch[In, Out any] struct {
+	inputs []In
+	// observers are the instrument.Observer of the caller of each input.
+	observers []instrument.Observer
+	outputs   []Out
+	err       error
+	// full is closed when the batch reaches the maximum size.
+	full chan struct{}
+	// done is closed when the batch has been processed.
+	done chan struct{}
+}
+
+func newBatcher[K comparable, In, Out any](
+	task string,
+	maxSize int,
+	maxWait time.Duration,
+	run func(ctx context.Context, key K, inputs []In) ([]Out, error),
+	single func(ctx context.Context, key K, input In) (Out, error),
+) *batcher[K, In, Out] {
+	return &batcher[K, In, Out]{
+		task:    task,
+		maxSize: maxSize,
+		maxWait: maxWait,
+		run:     run,
+		single:  single,
+		pending: make(map[K]*batch[In, Out]),
+	}
+}
+
+// do adds the input to the pending batch for the given key and returns its
+// output once the batch has been processed.
+func (b *batcher[K, In, Out]) do(ctx context.Context, key K, input In) (Out, error) {
+	bt, i, leader := b.add(key, input, instrument.FromContext(ctx))
+
+	if leader {
+		start := time.Now()
+		timer := time.NewTimer(b.maxWait)
+		select {
+		case <-bt.full:
+		case <-timer.C:
+		}
+		timer.Stop()
+		b.seal(key, bt)
+		instrument.Batch(ctx, b.task, len(bt.inputs), start)
+
+		// the other callers depend on this run: it must not be interrupted
+		// if the context of the leading caller is canceled.
+		o := &inputsObserver{
+			leader: instrument.FromContext(ctx),
+			inputs: bt.observers,
+		}
+		runCtx := instrument.WithObserver(context.WithoutCancel(ctx), o)
+		bt.outputs, bt.err = b.run(runCtx, key, bt.inputs)
+		if bt.err == nil && len(bt.outputs) != len(bt.inputs) {
+			bt.err = fmt.Errorf("batch produced %d results for %d inputs", len(bt.outputs), len(bt.inputs))
+		}
+		// the callers of a failed batch are served by single, which
+		// reports their tokens again
+		if bt.err == nil || len(bt.inputs) == 1 {
+			o.commit()
+		}
+		close(bt.done)
+	}
+
+	select {
+	case <-bt.done:
+	case <-ctx.Done():
+		var zero Out
+		return zero, ctx.Err()
+	}
+
+	if bt.err != nil {
+		if len(bt.inputs) > 1 {
+			// one bad input must not fail the whole batch
+			return b.single(ctx, key, input)
+		}
+		var zero Out
+		return zero, bt.err
+	}
+	return bt.outputs[i], nil
+}
+
+// add appends the input to the pending batch for the given key, creating a
+// new batch if necessary. It returns the batch, the index of the input within
+// the batch, and whether the caller is the leader of the batch.
+func (b *batcher[K, In, Out]) add(key K, input In, o instrument.Observer) (bt *batch[In, Out], i int, leader bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt, ok := b.pending[key]
+	if !ok {
+		bt = &batch[In, Out]{
+			inputs:    make([]In, 0, b.maxSize),
+			observers: make([]instrument.Observer, 0, b.maxSize),
+			full:      make(chan struct{}),
+			done:      make(chan struct{}),
+		}
+		b.pending[key] = bt
+		leader = true
+	}
+	i = len(bt.inputs)
+	bt.inputs = append(bt.inputs, input)
+	bt.observers = append(bt.observers, o)
+	if len(bt.inputs) >= b.maxSize {
+		delete(b.pending, key)
+		close(bt.full)
+	}
+	return bt, i, leader
+}
+
+// seal prevents further inputs from being added to the batch.
+func (b *batcher[K, In, Out]) seal(key K, bt *batch[In, Out]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending[key] == bt {
+		delete(b.pending, key)
+	}
+}
+
+// inputsObserver is the instrument.BatchObserver of a batch run. The tokens
+// of the i-th input are charged to the Observer of its caller, once the run
+// is committed, while the measurements of the whole batch go to the Observer
+// of the leading caller. The tokens reported without index are taken as
+// those of the inputs in order.
+type inputsObserver struct {
+	leader instrument.Observer
+	inputs []instrument.Observer
+
+	mu   sync.Mutex
+	next int
+	// tokens are the tokens reported for each input, until committed.
+	tokens []inputTokens
+}
+
+// inputTokens are the tokens reported for an input of a batch.
+type inputTokens struct {
+	task  string
+	input int
+	count int
+}
+
+func (o *inputsObserver) ObserveInputTokens(task string, count int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tokens = append(o.tokens, inputTokens{task: task, input: o.next, count: count})
+	o.next++
+}
+
+func (o *inputsObserver) ObserveBatchInputTokens(task string, i, count int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tokens = append(o.tokens, inputTokens{task: task, input: i, count: count})
+}
+
+// commit forwards the tokens reported so far to the Observer of the caller
+// of each input.
+func (o *inputsObserver) commit() {
+	o.mu.Lock()
+	tokens := o.tokens
+	o.tokens = nil
+	o.mu.Unlock()
+
+	for _, t := range tokens {
+		if t.input >= 0 && t.input < len(o.inputs) && o.inputs[t.input] != nil {
+			o.inputs[t.input].ObserveInputTokens(t.task, t.count)
+		}
+	}
+}
+
+func (o *inputsObserver) ObserveTokenization(task string, d time.Duration) {
+	if o.leader != nil {
+		o.leader.ObserveTokenization(task, d)
+	}
+}
+
+func (o *inputsObserver) ObserveForward(task string, d time.Duration) {
+	if o.leader != nil {
+		o.leader.ObserveForward(task, d)
+	}
+}
+
+func (o *inputsObserver) ObserveBatch(task string, size int, wait time.Duration) {
+	if o.leader != nil {
+		o.leader.ObserveBatch(task, size, wait)
+	}
+}
+
+func (o *inputsObserver) ObserveCache(task string, hit bool) {
+	if o.leader != nil {
+		o.leader.ObserveCache(task, hit)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+)
+
+func TestBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+
+	upper := func(_ context.Context, _ struct{}, inputs []string) ([]string, error) {
+		mu.Lock()
+		sizes = append(sizes, len(inputs))
+		mu.Unlock()
+		outputs := make([]string, len(inputs))
+		for i, in := range inputs {
+			outputs[i] = strings.ToUpper(in)
+		}
+		return outputs, nil
+	}
+	single := func(_ context.Context, _ struct{}, input string) (string, error) {
+		return strings.ToUpper(input), nil
+	}
+	b := newBatcher("test", 4, time.Second, upper, single)
+
+	inputs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	outputs := make([]string, len(inputs))
+	var wg sync.WaitGroup
+	for i, in := range inputs {
+		wg.Add(1)
+		go func(i int, in string) {
+			defer wg.Done()
+			out, err := b.do(context.Background(), struct{}{}, in)
+			assert.NoError(t, err)
+			outputs[i] = out
+		}(i, in)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"A", "B", "C", "D", "E", "F", "G", "H"}, outputs)
+	assert.Equal(t, []int{4, 4}, sizes)
+}
+
+func TestBatcher_MaxWait(t *testing.T) {
+	run := func(_ context.Context, _ struct{}, inputs []int) ([]int, error) {
+		return inputs, nil
+	}
+	single := func(_ context.Context, _ struct{}, input int) (int, error) {
+		return input, nil
+	}
+	b := newBatcher("test", 100, 10*time.Millisecond, run, single)
+
+	start := time.Now()
+	out, err := b.do(context.Background(), struct{}{}, 42)
+	require.NoError(t, err)
+	assert.Equal(t, 42, out)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestBatcher_ErrorIsolation(t *testing.T) {
+	errBad := errors.New("bad input")
+	run := func(_ context.Context, _ struct{}, inputs []string) ([]string, error) {
+		for _, in := range inputs {
+			if in == "bad" {
+				return nil, errBad
+			}
+		}
+		return inputs, nil
+	}
+	single := func(ctx context.Context, key struct{}, input string) (string, error) {
+		out, err := run(ctx, key, []string{input})
+		if err != nil {
+			return "", err
+		}
+		return out[0], nil
+	}
+	b := newBatcher("test", 2, time.Second, run, single)
+
+	var wg sync.WaitGroup
+	var goodErr, badErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, goodErr = b.do(context.Background(), struct{}{}, "good")
+	}()
+	go func() {
+		defer wg.Done()
+		_, badErr = b.do(context.Background(), struct{}{}, "bad")
+	}()
+	wg.Wait()
+
+	assert.NoError(t, goodErr)
+	assert.ErrorIs(t, badErr, errBad)
+}
+
+func TestBatcher_SeparateKeys(t *testing.T) {
+	run := func(_ context.Context, key int, inputs []int) ([]int, error) {
+		outputs := make([]int, len(inputs))
+		for i, in := range inputs {
+			outputs[i] = in * key
+		}
+		return outputs, nil
+	}
+	single := func(_ context.Context, key int, input int) (int, error) {
+		return input * key, nil
+	}
+	b := newBatcher("test", 2, time.Second, run, single)
+
+	var wg sync.WaitGroup
+	results := make([]int, 4)
+	for i, key := range []int{10, 100, 10, 100} {
+		wg.Add(1)
+		go func(i, key int) {
+			defer wg.Done()
+			out, err := b.do(context.Background(), key, i+1)
+			assert.NoError(t, err)
+			results[i] = out
+		}(i, key)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{10, 200, 30, 400}, results)
+}
+
+func TestBatcher_InputTokens(t *testing.T) {
+	run := func(ctx context.Context, _ struct{}, inputs []string) ([]string, error) {
+		for _, in := range inputs {
+			instrument.InputTokens(ctx, "test", len(in))
+		}
+		return inputs, nil
+	}
+	single := func(_ context.Context, _ struct{}, input string) (string, error) {
+		return input, nil
+	}
+	b := newBatcher("test", 2, time.Second, run, single)
+
+	quotas := []*tokenQuota{{limit: 100, period: time.Hour}, {limit: 100, period: time.Hour}}
+	var wg sync.WaitGroup
+	for i, in := range []string{"a", "bbb"} {
+		wg.Add(1)
+		go func(o instrument.Observer, in string) {
+			defer wg.Done()
+			_, err := b.do(instrument.WithObserver(context.Background(), o), struct{}{}, in)
+			assert.NoError(t, err)
+		}(quotas[i], in)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), quotas[0].used, "each caller is charged the tokens of its own input")
+	assert.Equal(t, int64(3), quotas[1].used)
+}
+
+func TestBatcher_InputTokensFailedBatch(t *testing.T) {
+	run := func(ctx context.Context, _ struct{}, inputs []string) ([]string, error) {
+		// reported out of order, before failing on the last input
+		for i := len(inputs) - 1; i >= 0; i-- {
+			instrument.BatchInputTokens(ctx, "test", i, len(inputs[i]))
+		}
+		return nil, errors.New("bad input")
+	}
+	single := func(ctx context.Context, _ struct{}, input string) (string, error) {
+		instrument.InputTokens(ctx, "test", len(input))
+		return input, nil
+	}
+	b := newBatcher("test", 2, time.Second, run, single)
+
+	quotas := []*tokenQuota{{limit: 100, period: time.Hour}, {limit: 100, period: time.Hour}}
+	errs := make([]error, len(quotas))
+	var wg sync.WaitGroup
+	for i, in := range []string{"a", "bbb"} {
+		wg.Add(1)
+		go func(i int, in string) {
+			defer wg.Done()
+			_, errs[i] = b.do(instrument.WithObserver(context.Background(), quotas[i]), struct{}{}, in)
+		}(i, in)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), quotas[0].used, "the tokens of the failed batch are not charged")
+	assert.Equal(t, int64(3), quotas[1].used)
+}
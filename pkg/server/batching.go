@@ -0,0 +1,121 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
+)
+
+// DefaultMaxBatchWait is the default maximum time a request waits for
+// other requests to join its batch, when dynamic batching is enabled.
+const DefaultMaxBatchWait = 5 * time.Millisecond
+
+// WithDynamicBatching wraps the model so that concurrent requests are
+// gathered and run as a single batched forward pass, according to the
+// MaxBatchSize and MaxBatchWait settings of the configuration.
+//
+// Batching is supported for text encoding, text classification and token
+// classification. The model is returned unchanged if batching is disabled
+// (MaxBatchSize <= 1) or not supported for its task.
+func WithDynamicBatching(model any, conf *Config) any {
+	if conf.MaxBatchSize <= 1 {
+		return model
+	}
+	maxWait := conf.MaxBatchWait
+	if maxWait <= 0 {
+		maxWait = DefaultMaxBatchWait
+	}
+
+	switch m := model.(type) {
+	case textclassification.Interface:
+		return newBatchingTextClassification(m, conf.MaxBatchSize, maxWait)
+	case textencoding.Interface:
+		return newBatchingTextEncoding(m, conf.MaxBatchSize, maxWait)
+	case tokenclassification.Interface:
+		return newBatchingTokenClassification(m, conf.MaxBatchSize, maxWait)
+	default:
+		return model
+	}
+}
+
+// batchingTextEncoding is a textencoding.Interface batching concurrent Encode calls.
+type batchingTextEncoding struct {
+	textencoding.Interface
+	batcher *batcher[int, string, textencoding.Response]
+}
+
+func newBatchingTextEncoding(m textencoding.Interface, maxSize int, maxWait time.Duration) *batchingTextEncoding {
+	return &batchingTextEncoding{
+		Interface: m,
+		batcher: newBatcher("text-encoding", maxSize, maxWait,
+			func(ctx context.Context, poolingStrategy int, texts []string) ([]textencoding.Response, error) {
+				return m.EncodeBatch(ctx, texts, poolingStrategy)
+			},
+			func(ctx context.Context, poolingStrategy int, text string) (textencoding.Response, error) {
+				return m.Encode(ctx, text, poolingStrategy)
+			},
+		),
+	}
+}
+
+// Encode adds the text to the next batch to encode.
+func (m *batchingTextEncoding) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	return m.batcher.do(ctx, poolingStrategy, text)
+}
+
+// batchingTextClassification is a textclassification.Interface batching concurrent Classify calls.
+type batchingTextClassification struct {
+	textclassification.Interface
+	batcher *batcher[struct{}, string, textclassification.Response]
+}
+
+func newBatchingTextClassification(m textclassification.Interface, maxSize int, maxWait time.Duration) *batchingTextClassification {
+	return &batchingTextClassification{
+		Interface: m,
+		batcher: newBatcher("text-classification", maxSize, maxWait,
+			func(ctx context.Context, _ struct{}, texts []string) ([]textclassification.Response, error) {
+				return m.ClassifyBatch(ctx, texts)
+			},
+			func(ctx context.Context, _ struct{}, text string) (textclassification.Response, error) {
+				return m.Classify(ctx, text)
+			},
+		),
+	}
+}
+
+// Classify adds the text to the next batch to classify.
+func (m *batchingTextClassification) Classify(ctx context.Context, text string) (textclassification.Response, error) {
+	return m.batcher.do(ctx, struct{}{}, text)
+}
+
+// batchingTokenClassification is a tokenclassification.Interface batching concurrent Classify calls.
+type batchingTokenClassification struct {
+	tokenclassification.Interface
+	batcher *batcher[tokenclassification.Parameters, string, tokenclassification.Response]
+}
+
+func newBatchingTokenClassification(m tokenclassification.Interface, maxSize int, maxWait time.Duration) *batchingTokenClassification {
+	return &batchingTokenClassification{
+		Interface: m,
+		batcher: newBatcher("token-classification", maxSize, maxWait,
+			func(ctx context.Context, params tokenclassification.Parameters, texts []string) ([]tokenclassification.Response, error) {
+				return m.ClassifyBatch(ctx, texts, params)
+			},
+			func(ctx context.Context, params tokenclassification.Parameters, text string) (tokenclassification.Response, error) {
+				return m.Classify(ctx, text, params)
+			},
+		),
+	}
+}
+
+// Classify adds the text to the next batch to classify.
+func (m *batchingTokenClassification) Classify(ctx context.Context, text string, parameters tokenclassification.Parameters) (tokenclassification.Response, error) {
+	return m.batcher.do(ctx, parameters, text)
+}
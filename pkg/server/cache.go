@@ -0,0 +1,140 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheKey identifies a request in a responseCache: the hash of its input
+// and normalized parameters.
+type cacheKey [sha256.Size]byte
+
+// newCacheKey returns the key of the request made of the given parts. It
+// returns false if the parts cannot be encoded, and so cannot be cached.
+func newCacheKey(parts ...any) (cacheKey, bool) {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		return cacheKey{}, false
+	}
+	return sha256.Sum256(data), true
+}
+
+// responseCache is an LRU cache of the responses of a model, expiring after
+// a TTL. Concurrent requests for the same key share a single computation.
+//
+// The cached responses are shared by all the callers, which must not modify
+// them.
+type responseCache[V any] struct {
+	// task is the name of the task reported to the instrument.Observer.
+	task string
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// lru holds the entries from the most to the least recently used.
+	lru     *list.List
+	flights singleflight.Group
+}
+
+type cacheEntry[V any] struct {
+	key     cacheKey
+	value   V
+	expires time.Time
+}
+
+func newResponseCache[V any](task string, size int, ttl time.Duration) *responseCache[V] {
+	return &responseCache[V]{
+		task:    task,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[cacheKey]*list.Element, size),
+		lru:     list.New(),
+	}
+}
+
+// do returns the cached response for the key, or computes and caches it.
+// Failed computations are not cached.
+func (c *responseCache[V]) do(ctx context.Context, key cacheKey, compute func(context.Context) (V, error)) (V, error) {
+	if v, ok := c.get(key); ok {
+		instrument.Cache(ctx, c.task, true)
+		return v, nil
+	}
+
+	leader := false
+	ch := c.flights.DoChan(string(key[:]), func() (any, error) {
+		leader = true
+		// the other callers depend on this computation: it must not be
+		// interrupted if the context of the leading caller is canceled.
+		v, err := compute(context.WithoutCancel(ctx))
+		if err == nil {
+			c.add(key, v)
+		}
+		return v, err
+	})
+
+	select {
+	case r := <-ch:
+		instrument.Cache(ctx, c.task, !leader)
+		if r.Err != nil {
+			var zero V
+			return zero, r.Err
+		}
+		return r.Val.(V), nil
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// get returns the response cached for the key, if not expired.
+func (c *responseCache[V]) get(key cacheKey) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry[V])
+		if c.ttl <= 0 || time.Now().Before(entry.expires) {
+			c.lru.MoveToFront(el)
+			return entry.value, true
+		}
+		c.remove(el)
+	}
+	var zero V
+	return zero, false
+}
+
+// add caches the response, evicting the least recently used one if the
+// cache is full.
+func (c *responseCache[V]) add(key cacheKey, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry[V]{key: key, value: v, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove deletes the entry. The caller must hold mu.
+func (c *responseCache[V]) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry[V]).key)
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"github.com/yinziyang/cybertron/pkg/utils/nullable"
+)
+
+func TestResponseCache(t *testing.T) {
+	c := newResponseCache[string]("test", 2, 0)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	get := func(input string) string {
+		key, ok := newCacheKey(input)
+		require.True(t, ok)
+		v, err := c.do(ctx, key, func(context.Context) (string, error) {
+			calls.Add(1)
+			return input + "!", nil
+		})
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, "a!", get("a"))
+	assert.Equal(t, "a!", get("a"))
+	assert.Equal(t, int32(1), calls.Load())
+
+	get("b")
+	get("a") // a is now the most recently used
+	get("c") // evicts b
+	assert.Equal(t, int32(3), calls.Load())
+	get("a")
+	assert.Equal(t, int32(3), calls.Load())
+	get("b")
+	assert.Equal(t, int32(4), calls.Load())
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		key, _ := newCacheKey("fail")
+		for i := 0; i < 2; i++ {
+			_, err := c.do(ctx, key, func(context.Context) (string, error) {
+				calls.Add(1)
+				return "", errors.New("boom")
+			})
+			assert.Error(t, err)
+		}
+		assert.Equal(t, int32(6), calls.Load())
+	})
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	c := newResponseCache[int]("test", 10, 10*time.Millisecond)
+	key, _ := newCacheKey("a")
+	var calls int
+	compute := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, _ := c.do(context.Background(), key, compute)
+	assert.Equal(t, 1, v)
+	v, _ = c.do(context.Background(), key, compute)
+	assert.Equal(t, 1, v)
+
+	time.Sleep(20 * time.Millisecond)
+	v, _ = c.do(context.Background(), key, compute)
+	assert.Equal(t, 2, v, "the entry expired")
+}
+
+func TestResponseCache_SingleFlight(t *testing.T) {
+	c := newResponseCache[string]("test", 10, 0)
+	key, _ := newCacheKey("a")
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	compute := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "A", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.do(context.Background(), key, compute)
+			assert.NoError(t, err)
+			assert.Equal(t, "A", v)
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "concurrent identical requests share one computation")
+}
+
+type countingGenerator struct {
+	textgeneration.Interface
+	calls int
+}
+
+func (g *countingGenerator) Generate(context.Context, string, *textgeneration.Options) (textgeneration.Response, error) {
+	g.calls++
+	return textgeneration.Response{Texts: []string{"generated"}}, nil
+}
+
+func TestWithResponseCache(t *testing.T) {
+	assert.IsType(t, &countingGenerator{}, WithResponseCache(&countingGenerator{}, &Config{}), "disabled")
+
+	g := &countingGenerator{}
+	m := WithResponseCache(g, &Config{CacheSize: 10}).(textgeneration.Interface)
+	ctx := context.Background()
+
+	_, _ = m.Generate(ctx, "input", nil)
+	_, _ = m.Generate(ctx, "input", &textgeneration.Options{})
+	assert.Equal(t, 1, g.calls, "nil and empty options are the same")
+
+	_, _ = m.Generate(ctx, "input", &textgeneration.Options{NumBeams: nullable.Type[int]{Value: 2, Valid: true}})
+	assert.Equal(t, 2, g.calls)
+
+	sampling := &textgeneration.Options{Sample: nullable.Type[bool]{Value: true, Valid: true}}
+	_, _ = m.Generate(ctx, "input", sampling)
+	_, _ = m.Generate(ctx, "input", sampling)
+	assert.Equal(t, 4, g.calls, "sampling is not cached")
+}
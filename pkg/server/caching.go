@@ -0,0 +1,170 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+
+	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
+	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
+)
+
+// WithResponseCache wraps the model so that its responses are cached, up to
+// CacheSize responses for CacheTTL, according to the configuration. The
+// responses are keyed on the input and the normalized parameters, and
+// concurrent identical requests share a single forward pass.
+//
+// Text generation with sampling, being non-deterministic, is never cached,
+// nor are the streaming and batch calls. The model is returned unchanged if
+// caching is disabled (CacheSize <= 0).
+func WithResponseCache(model any, conf *Config) any {
+	if conf.CacheSize <= 0 {
+		return model
+	}
+	size, ttl := conf.CacheSize, conf.CacheTTL
+
+	// same precedence as ResolveRequestHandler
+	switch m := model.(type) {
+	case textgeneration.Interface:
+		return &cachingTextGeneration{Interface: m, cache: newResponseCache[textgeneration.Response]("text-generation", size, ttl)}
+	case zeroshotclassifier.Interface:
+		return &cachingZeroShotClassifier{Interface: m, cache: newResponseCache[zeroshotclassifier.Response]("zero-shot-classification", size, ttl)}
+	case questionanswering.Interface:
+		return &cachingQuestionAnswering{Interface: m, cache: newResponseCache[questionanswering.Response]("question-answering", size, ttl)}
+	case textclassification.Interface:
+		return &cachingTextClassification{Interface: m, cache: newResponseCache[textclassification.Response]("text-classification", size, ttl)}
+	case textencoding.Interface:
+		return &cachingTextEncoding{Interface: m, cache: newResponseCache[textencoding.Response]("text-encoding", size, ttl)}
+	case tokenclassification.Interface:
+		return &cachingTokenClassification{Interface: m, cache: newResponseCache[tokenclassification.Response]("token-classification", size, ttl)}
+	case languagemodeling.Interface:
+		return &cachingLanguageModeling{Interface: m, cache: newResponseCache[languagemodeling.Response]("language-modeling", size, ttl)}
+	default:
+		return model
+	}
+}
+
+// cached returns the response of the request made of the given parts from
+// the cache, or computes it, bypassing the cache if the parts cannot be
+// encoded.
+func cached[V any](ctx context.Context, c *responseCache[V], compute func(context.Context) (V, error), parts ...any) (V, error) {
+	key, ok := newCacheKey(parts...)
+	if !ok {
+		return compute(ctx)
+	}
+	return c.do(ctx, key, compute)
+}
+
+// cachingTextEncoding is a textencoding.Interface caching the Encode responses.
+type cachingTextEncoding struct {
+	textencoding.Interface
+	cache *responseCache[textencoding.Response]
+}
+
+// Encode returns the cached encoding of the text, or computes it.
+func (m *cachingTextEncoding) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	return cached(ctx, m.cache, func(ctx context.Context) (textencoding.Response, error) {
+		return m.Interface.Encode(ctx, text, poolingStrategy)
+	}, text, poolingStrategy)
+}
+
+// cachingTextClassification is a textclassification.Interface caching the Classify responses.
+type cachingTextClassification struct {
+	textclassification.Interface
+	cache *responseCache[textclassification.Response]
+}
+
+// Classify returns the cached classification of the text, or computes it.
+func (m *cachingTextClassification) Classify(ctx context.Context, text string) (textclassification.Response, error) {
+	return cached(ctx, m.cache, func(ctx context.Context) (textclassification.Response, error) {
+		return m.Interface.Classify(ctx, text)
+	}, text)
+}
+
+// cachingTokenClassification is a tokenclassification.Interface caching the Classify responses.
+type cachingTokenClassification struct {
+	tokenclassification.Interface
+	cache *responseCache[tokenclassification.Response]
+}
+
+// Classify returns the cached classification of the text, or computes it.
+func (m *cachingTokenClassification) Classify(ctx context.Context, text string, parameters tokenclassification.Parameters) (tokenclassification.Response, error) {
+	return cached(ctx, m.cache, func(ctx context.Context) (tokenclassification.Response, error) {
+		return m.Interface.Classify(ctx, text, parameters)
+	}, text, parameters)
+}
+
+// cachingZeroShotClassifier is a zeroshotclassifier.Interface caching the Classify responses.
+type cachingZeroShotClassifier struct {
+	zeroshotclassifier.Interface
+	cache *responseCache[zeroshotclassifier.Response]
+}
+
+// Classify returns the cached classification of the text, or computes it.
+func (m *cachingZeroShotClassifier) Classify(ctx context.Context, text string, parameters zeroshotclassifier.Parameters) (zeroshotclassifier.Response, error) {
+	normalized := parameters
+	if normalized.HypothesisTemplate == "" {
+		normalized.HypothesisTemplate = zeroshotclassifier.DefaultHypothesisTemplate
+	}
+	return cached(ctx, m.cache, func(ctx context.Context) (zeroshotclassifier.Response, error) {
+		return m.Interface.Classify(ctx, text, parameters)
+	}, text, normalized)
+}
+
+// cachingQuestionAnswering is a questionanswering.Interface caching the ExtractAnswer responses.
+type cachingQuestionAnswering struct {
+	questionanswering.Interface
+	cache *responseCache[questionanswering.Response]
+}
+
+// ExtractAnswer returns the cached answers to the question, or computes them.
+func (m *cachingQuestionAnswering) ExtractAnswer(ctx context.Context, question, passage string, opts *questionanswering.Options) (questionanswering.Response, error) {
+	var normalized questionanswering.Options
+	if opts != nil {
+		normalized = *opts
+	}
+	return cached(ctx, m.cache, func(ctx context.Context) (questionanswering.Response, error) {
+		return m.Interface.ExtractAnswer(ctx, question, passage, opts)
+	}, question, passage, normalized)
+}
+
+// cachingLanguageModeling is a languagemodeling.Interface caching the Predict responses.
+type cachingLanguageModeling struct {
+	languagemodeling.Interface
+	cache *responseCache[languagemodeling.Response]
+}
+
+// Predict returns the cached predictions for the text, or computes them.
+func (m *cachingLanguageModeling) Predict(ctx context.Context, text string, parameters languagemodeling.Parameters) (languagemodeling.Response, error) {
+	return cached(ctx, m.cache, func(ctx context.Context) (languagemodeling.Response, error) {
+		return m.Interface.Predict(ctx, text, parameters)
+	}, text, parameters)
+}
+
+// cachingTextGeneration is a textgeneration.Interface caching the Generate
+// responses, unless sampling is enabled.
+type cachingTextGeneration struct {
+	textgeneration.Interface
+	cache *responseCache[textgeneration.Response]
+}
+
+// Generate returns the cached generation for the text, or computes it.
+func (m *cachingTextGeneration) Generate(ctx context.Context, text string, opts *textgeneration.Options) (textgeneration.Response, error) {
+	var normalized textgeneration.Options
+	if opts != nil {
+		normalized = *opts
+	}
+	if normalized.Sample.Valid && normalized.Sample.Value {
+		return m.Interface.Generate(ctx, text, opts)
+	}
+	return cached(ctx, m.cache, func(ctx context.Context) (textgeneration.Response, error) {
+		return m.Interface.Generate(ctx, text, opts)
+	}, text, normalized)
+}
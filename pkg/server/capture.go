@@ -0,0 +1,233 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// DefaultCaptureMaxFileSize is the default size in bytes after which a
+	// capture file is rotated.
+	DefaultCaptureMaxFileSize = 64 << 20
+	// DefaultCaptureMaxFiles is the default number of capture files kept.
+	DefaultCaptureMaxFiles = 10
+)
+
+const (
+	captureFilePrefix     = "capture-"
+	captureFileExt        = ".jsonl"
+	captureFileTimeLayout = "20060102T150405.000000000Z"
+)
+
+// captureRecord is a line of a capture file. The method and the request
+// have the same shape as the ones of a SubmitJobRequest, so that a capture
+// file can be replayed as jobs by an offline batch runner.
+type captureRecord struct {
+	Time         time.Time       `json:"time"`
+	RequestID    string          `json:"request_id"`
+	Method       string          `json:"method"`
+	Model        string          `json:"model,omitempty"`
+	ModelVersion string          `json:"model_version,omitempty"`
+	LatencyMS    float64         `json:"latency_ms"`
+	Request      json.RawMessage `json:"request"`
+	Response     json.RawMessage `json:"response"`
+}
+
+// captureSink writes a sample of the requests served, with their responses,
+// to JSON Lines files in a directory, rotated by size. The files are named
+// after the time they were created, so that they sort in order.
+type captureSink struct {
+	dir         string
+	sampleRate  float64
+	maxFileSize int64
+	maxFiles    int
+	redact      Redactor
+	now         func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newCaptureSink(conf *Config) (*captureSink, error) {
+	if err := os.MkdirAll(conf.CaptureDir, 0o755); err != nil {
+		return nil, err
+	}
+	c := &captureSink{
+		dir:         conf.CaptureDir,
+		sampleRate:  conf.CaptureSampleRate,
+		maxFileSize: conf.CaptureMaxFileSize,
+		maxFiles:    conf.CaptureMaxFiles,
+		redact:      conf.CaptureRedactor,
+		now:         time.Now,
+	}
+	if c.sampleRate <= 0 {
+		c.sampleRate = 1
+	}
+	if c.maxFileSize <= 0 {
+		c.maxFileSize = DefaultCaptureMaxFileSize
+	}
+	if c.maxFiles <= 0 {
+		c.maxFiles = DefaultCaptureMaxFiles
+	}
+	return c, nil
+}
+
+// sample reports whether a request is to be captured.
+func (c *captureSink) sample() bool {
+	return c.sampleRate >= 1 || rand.Float64() < c.sampleRate
+}
+
+// write captures the request of the access log entry, logging the errors,
+// which do not fail the request. It must be called with the mutex of the
+// entry held.
+func (c *captureSink) write(e *accessEntry, latency time.Duration) {
+	line, err := c.marshal(e, latency)
+	if err == nil {
+		err = c.writeLine(line)
+	}
+	if err != nil {
+		log.Err(err).Str("request_id", e.id).Msg("failed to capture request")
+	}
+}
+
+// marshal returns the line of the capture record of the entry.
+func (c *captureSink) marshal(e *accessEntry, latency time.Duration) ([]byte, error) {
+	req, err := c.payload(e.request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.payload(e.response)
+	if err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(captureRecord{
+		Time:         e.start.UTC(),
+		RequestID:    e.id,
+		Method:       e.method,
+		Model:        e.model,
+		ModelVersion: e.version,
+		LatencyMS:    float64(latency) / float64(time.Millisecond),
+		Request:      req,
+		Response:     resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// payload returns the payload redacted and marshaled along with its type,
+// as a google.protobuf.Any.
+func (c *captureSink) payload(m proto.Message) ([]byte, error) {
+	if c.redact != nil {
+		m = proto.Clone(m)
+		c.redact(m)
+	}
+	packed, err := anypb.New(m)
+	if err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(packed)
+}
+
+// writeLine appends the line to the current file, rotating it first if the
+// line would exceed the maximum file size.
+func (c *captureSink) writeLine(line []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file != nil && c.size+int64(len(line)) > c.maxFileSize {
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	if c.file == nil {
+		if err := c.open(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(line)
+	c.size += int64(n)
+	return err
+}
+
+// rotate closes the current file. It must be called with the mutex held.
+func (c *captureSink) rotate() error {
+	err := c.file.Close()
+	c.file, c.size = nil, 0
+	return err
+}
+
+// open creates a new file, deleting the oldest ones beyond the maximum
+// number of files. It must be called with the mutex held.
+func (c *captureSink) open() error {
+	name := filepath.Join(c.dir, captureFilePrefix+c.now().UTC().Format(captureFileTimeLayout)+captureFileExt)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	c.file, c.size = f, info.Size()
+	return c.prune()
+}
+
+// prune deletes the oldest capture files beyond the maximum number of files.
+func (c *captureSink) prune() error {
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+	for len(files) > c.maxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to delete capture file: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// files returns the paths of the capture files, oldest first.
+func (c *captureSink) files() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasPrefix(name, captureFilePrefix) && strings.HasSuffix(name, captureFileExt) {
+			files = append(files, filepath.Join(c.dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// close closes the current file, if any.
+func (c *captureSink) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	return c.rotate()
+}
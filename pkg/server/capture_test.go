@@ -0,0 +1,122 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// readCaptures returns the records of the capture files in the directory.
+func readCaptures(dir string) ([]map[string]any, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "capture-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var records []map[string]any
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			record := map[string]any{}
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func TestCaptureSink_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newCaptureSink(&Config{CaptureDir: dir, CaptureMaxFileSize: 10, CaptureMaxFiles: 2})
+	require.NoError(t, err)
+	now := time.Now()
+	c.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		require.NoError(t, c.writeLine([]byte(line)))
+	}
+	require.NoError(t, c.close())
+
+	files, err := c.files()
+	require.NoError(t, err)
+	require.Len(t, files, 2, "the oldest file is deleted")
+	for i, want := range []string{"second\n", "third\n"} {
+		data, err := os.ReadFile(files[i])
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+}
+
+func TestServer_Capture(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRegistry()
+	require.NoError(t, r.AddVersion("embeddings", "v2", instrumentedEncoder{}))
+	s := startServer(t, &Config{CaptureDir: dir, CaptureRedactor: RedactStrings}, r)
+
+	conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = textencodingv1.NewTextEncodingServiceClient(conn).Encode(context.Background(),
+		&textencodingv1.EncodingRequest{Input: "top secret"})
+	require.NoError(t, err)
+
+	resp, err := http.Post("http://"+s.ClientAddr()+"/v1/encode", "application/json", strings.NewReader(`{"input": "top secret"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = http.Post("http://"+s.ClientAddr()+"/v1/encode", "application/json", strings.NewReader(`{"input": "a", "model": "unknown"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NotEqual(t, http.StatusOK, resp.StatusCode)
+
+	var records []map[string]any
+	require.Eventually(t, func() bool {
+		var err error
+		records, err = readCaptures(dir)
+		return err == nil && len(records) == 2
+	}, time.Second, time.Millisecond, "the failed requests are not captured")
+	for _, record := range records {
+		assert.Equal(t, "/textencoding.v1.TextEncodingService/Encode", record["method"])
+		assert.Equal(t, "embeddings", record["model"])
+		assert.Equal(t, "v2", record["model_version"])
+		assert.NotEmpty(t, record["request_id"])
+		assert.Contains(t, record, "time")
+		assert.Contains(t, record, "latency_ms")
+		assert.Equal(t, map[string]any{
+			"@type": "type.googleapis.com/textencoding.v1.EncodingRequest",
+			"input": "[redacted 10 chars]",
+		}, record["request"])
+		assert.Equal(t, map[string]any{
+			"@type":  "type.googleapis.com/textencoding.v1.EncodingResponse",
+			"vector": []any{float64(1), float64(2)},
+		}, record["response"])
+	}
+}
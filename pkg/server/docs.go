@@ -0,0 +1,130 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// openAPIPath is the HTTP path serving the OpenAPI document of the
+	// services of the server.
+	openAPIPath = "/openapi.json"
+	// docsPath is the HTTP path serving the Swagger UI page rendering the
+	// OpenAPI document.
+	docsPath = "/docs"
+)
+
+// openAPIDocs are the OpenAPI v2 documents generated from the protos, one
+// for each service.
+//
+//go:embed gen/openapiv2
+var openAPIDocs embed.FS
+
+//go:embed swaggerui.html
+var swaggerUIPage []byte
+
+// openAPIDocument is the subset of an OpenAPI v2 document merged by
+// newOpenAPIDocument.
+type openAPIDocument struct {
+	Swagger     string                     `json:"swagger"`
+	Info        map[string]string          `json:"info"`
+	Tags        []openAPITag               `json:"tags,omitempty"`
+	Consumes    []string                   `json:"consumes,omitempty"`
+	Produces    []string                   `json:"produces,omitempty"`
+	Paths       map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]json.RawMessage `json:"definitions"`
+}
+
+type openAPITag struct {
+	Name string `json:"name"`
+}
+
+// newOpenAPIDocument returns the OpenAPI document of the given gRPC services,
+// merging their generated documents. The services are identified by their
+// full name (e.g. "textencoding.v1.TextEncodingService"); the ones without a
+// document, such as the health service, are ignored.
+//
+// A path shared by several services (e.g. "/v1/classify") is documented by
+// the first of them, in the alphabetical order of their packages.
+func newOpenAPIDocument(services map[string]grpc.ServiceInfo) ([]byte, error) {
+	names := make(map[string]bool, len(services))
+	for name := range services {
+		// the documents tag the operations with the short service name
+		names[name[strings.LastIndex(name, ".")+1:]] = true
+	}
+
+	merged := &openAPIDocument{
+		Swagger:     "2.0",
+		Info:        map[string]string{"title": "Cybertron", "version": "v1"},
+		Consumes:    []string{"application/json"},
+		Produces:    []string{"application/json"},
+		Paths:       make(map[string]json.RawMessage),
+		Definitions: make(map[string]json.RawMessage),
+	}
+	err := fs.WalkDir(openAPIDocs, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+			return err
+		}
+		data, err := openAPIDocs.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var doc openAPIDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if !servesAny(names, doc.Tags) {
+			return nil
+		}
+		merged.Tags = append(merged.Tags, doc.Tags...)
+		for k, v := range doc.Paths {
+			if _, exists := merged.Paths[k]; !exists {
+				merged.Paths[k] = v
+			}
+		}
+		for k, v := range doc.Definitions {
+			merged.Definitions[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(merged.Tags, func(i, j int) bool { return merged.Tags[i].Name < merged.Tags[j].Name })
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// servesAny reports whether any of the tags names a served service.
+func servesAny(names map[string]bool, tags []openAPITag) bool {
+	for _, tag := range tags {
+		if names[tag.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIHandler returns the handler of openAPIPath, serving the document.
+func openAPIHandler(doc []byte) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(doc)
+	}
+}
+
+// docsHandler serves docsPath, the Swagger UI page rendering openAPIPath.
+func docsHandler(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(swaggerUIPage)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	data, err := newOpenAPIDocument(map[string]grpc.ServiceInfo{
+		"grpc.health.v1.Health":                             {},
+		"textclassification.v1.TextClassificationService":   {},
+		"tokenclassification.v1.TokenClassificationService": {},
+		"textencoding.v1.TextEncodingService":               {},
+	})
+	require.NoError(t, err)
+
+	var doc struct {
+		Tags        []openAPITag
+		Paths       map[string]map[string]struct{ OperationID string }
+		Definitions map[string]json.RawMessage
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, []openAPITag{{"TextClassificationService"}, {"TextEncodingService"}, {"TokenClassificationService"}}, doc.Tags)
+	assert.Contains(t, doc.Paths, "/v1/encode")
+	assert.Contains(t, doc.Paths, "/v1/token-classification/classify")
+	assert.NotContains(t, doc.Paths, "/v1/generate", "not served")
+	assert.Equal(t, "TextClassificationService_Classify", doc.Paths["/v1/classify"]["post"].OperationID)
+	assert.Contains(t, doc.Definitions, "textclassification.v1.ClassifyRequest")
+	assert.Contains(t, doc.Definitions, "tokenclassification.v1.ClassifyRequest")
+}
+
+func TestServer_Docs(t *testing.T) {
+	s := startServer(t, &Config{ReflectionEnabled: true}, NewServerForTextEncoding(instrumentedEncoder{}))
+	baseURL := "http://" + s.ClientAddr()
+
+	resp, err := http.Get(baseURL + "/openapi.json")
+	require.NoError(t, err)
+	var doc struct{ Paths map[string]json.RawMessage }
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	resp.Body.Close()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Contains(t, doc.Paths, "/v1/encode")
+
+	resp, err = http.Get(baseURL + "/docs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+
+	conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	}))
+	reply, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, service := range reply.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, "textencoding.v1.TextEncodingService")
+}
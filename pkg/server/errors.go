@@ -0,0 +1,184 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
+	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo details of the errors.
+const ErrorDomain = "cybertron"
+
+// Reasons of the google.rpc.ErrorInfo details of the errors.
+const (
+	// ReasonInputSequenceTooLong means that the input is longer than the
+	// maximum length allowed by the model. The "length" and "max_length"
+	// metadata report the lengths in tokens.
+	ReasonInputSequenceTooLong = "INPUT_SEQUENCE_TOO_LONG"
+	// ReasonInvalidOptions means that the request parameters are invalid.
+	ReasonInvalidOptions = "INVALID_OPTIONS"
+	// ReasonTooManyCharacters means that a text of the request is longer
+	// than the maximum number of characters allowed by the server.
+	ReasonTooManyCharacters = "TOO_MANY_CHARACTERS"
+	// ReasonTooManyCandidateLabels means that a zero-shot classification
+	// request has more candidate labels than allowed by the server.
+	ReasonTooManyCandidateLabels = "TOO_MANY_CANDIDATE_LABELS"
+	// ReasonTooManyInputs means that a batch request has more inputs than
+	// allowed by the server.
+	ReasonTooManyInputs = "TOO_MANY_INPUTS"
+	// ReasonQueueFull means that too many requests are waiting to be served.
+	ReasonQueueFull = "QUEUE_FULL"
+	// ReasonQueueTimeout means that the request waited too long to be served.
+	ReasonQueueTimeout = "QUEUE_TIMEOUT"
+	// ReasonRateLimited means that the client sent more requests than
+	// allowed by the rate limit of its API key.
+	ReasonRateLimited = "RATE_LIMITED"
+	// ReasonTokenQuotaExceeded means that the client used up the quota of
+	// input tokens of its API key for the current period.
+	ReasonTokenQuotaExceeded = "TOKEN_QUOTA_EXCEEDED"
+	// ReasonNotReady means that the server is not ready to serve requests
+	// yet, as its models are still loading.
+	ReasonNotReady = "NOT_READY"
+)
+
+// inputSequenceTooLongErrors are the ErrInputSequenceTooLong errors of the tasks.
+var inputSequenceTooLongErrors = []error{
+	languagemodeling.ErrInputSequenceTooLong,
+	questionanswering.ErrInputSequenceTooLong,
+	textclassification.ErrInputSequenceTooLong,
+	textencoding.ErrInputSequenceTooLong,
+	textgeneration.ErrInputSequenceTooLong,
+	tokenclassification.ErrInputSequenceTooLong,
+	zeroshotclassifier.ErrInputSequenceTooLong,
+}
+
+// statusError translates the error returned by a task into a gRPC status
+// error, with google.rpc error details when available. Errors that already
+// carry a status are returned unchanged.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case isInputSequenceTooLong(err):
+		return inputSequenceTooLongStatus(err)
+	case errors.Is(err, textgeneration.ErrInvalidOptions):
+		return withDetails(status.New(codes.InvalidArgument, err.Error()),
+			&errdetails.ErrorInfo{Reason: ReasonInvalidOptions, Domain: ErrorDomain},
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "parameters", Description: err.Error()},
+			}},
+		)
+	case errors.Is(err, textencoding.ErrInvalidPoolingStrategy):
+		return withDetails(status.New(codes.InvalidArgument, err.Error()),
+			&errdetails.ErrorInfo{Reason: ReasonInvalidOptions, Domain: ErrorDomain},
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "pooling_strategy", Description: err.Error()},
+			}},
+		)
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func isInputSequenceTooLong(err error) bool {
+	for _, target := range inputSequenceTooLongErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// inputSequenceTooLongStatus returns the InvalidArgument status of an input
+// sequence too long, detailing the lengths if known.
+func inputSequenceTooLongStatus(err error) error {
+	info := &errdetails.ErrorInfo{Reason: ReasonInputSequenceTooLong, Domain: ErrorDomain}
+	violation := &errdetails.BadRequest_FieldViolation{Field: "input", Description: err.Error()}
+
+	var tooLong *taskerrors.InputTooLongError
+	if errors.As(err, &tooLong) {
+		info.Metadata = map[string]string{
+			"length":     strconv.Itoa(tooLong.Length),
+			"max_length": strconv.Itoa(tooLong.MaxLength),
+		}
+		if tooLong.Index >= 0 {
+			violation.Field = "inputs[" + strconv.Itoa(tooLong.Index) + "]"
+		}
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, err.Error()), info,
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{violation}})
+}
+
+// withDetails returns the error of the status with the given details.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.Err(err).Msg("failed to add error details")
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// errorsUnaryServerInterceptor returns a gRPC interceptor translating the
+// errors of unary calls with statusError.
+func errorsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, statusError(err)
+	}
+}
+
+// errorsStreamServerInterceptor returns a gRPC interceptor translating the
+// errors of streaming calls with statusError.
+func errorsStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return statusError(handler(srv, ss))
+	}
+}
+
+// httpErrorHandler is the error handler of the gateway mux, translating the
+// errors with statusError before rendering them as JSON, details included.
+func httpErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	err = statusError(err)
+	setHTTPCallCode(ctx, status.Code(err))
+	logError(ctx, err)
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// writeHTTPError renders the error of a request failed outside the mux, as
+// if it were returned by the mux.
+func writeHTTPError(mux *runtime.ServeMux, w http.ResponseWriter, r *http.Request, err error) {
+	_, outbound := runtime.MarshalerForRequest(mux, r)
+	httpErrorHandler(r.Context(), mux, outbound, w, r, err)
+}
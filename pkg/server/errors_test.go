@@ -0,0 +1,98 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{taskerrors.InputTooLong(textencoding.ErrInputSequenceTooLong, 600, 512), codes.InvalidArgument},
+		{fmt.Errorf("wrapped: %w", textgeneration.ErrInputSequenceTooLong), codes.InvalidArgument},
+		{fmt.Errorf("%w: num beams must be positive", textgeneration.ErrInvalidOptions), codes.InvalidArgument},
+		{fmt.Errorf("bert: %w 7", textencoding.ErrInvalidPoolingStrategy), codes.InvalidArgument},
+		{context.Canceled, codes.Canceled},
+		{fmt.Errorf("decoding: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{status.Error(codes.ResourceExhausted, "too many requests"), codes.ResourceExhausted},
+		{errors.New("boom"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(statusError(tt.err)))
+		})
+	}
+
+	t.Run("details", func(t *testing.T) {
+		err := statusError(taskerrors.InputTooLongAt(textencoding.ErrInputSequenceTooLong, 2, 600, 512))
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+
+		info := details[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, ReasonInputSequenceTooLong, info.GetReason())
+		assert.Equal(t, map[string]string{"length": "600", "max_length": "512"}, info.GetMetadata())
+
+		violations := details[1].(*errdetails.BadRequest).GetFieldViolations()
+		require.Len(t, violations, 1)
+		assert.Equal(t, "inputs[2]", violations[0].GetField())
+	})
+
+	t.Run("pooling strategy details", func(t *testing.T) {
+		err := statusError(fmt.Errorf("bert: %w 7", textencoding.ErrInvalidPoolingStrategy))
+		details := status.Convert(err).Details()
+		require.Len(t, details, 2)
+		violations := details[1].(*errdetails.BadRequest).GetFieldViolations()
+		require.Len(t, violations, 1)
+		assert.Equal(t, "pooling_strategy", violations[0].GetField())
+	})
+}
+
+type tooLongEncoder struct {
+	fakeEncoder
+}
+
+func (tooLongEncoder) Encode(context.Context, string, int) (textencoding.Response, error) {
+	return textencoding.Response{}, taskerrors.InputTooLong(textencoding.ErrInputSequenceTooLong, 600, 512)
+}
+
+func TestServer_HTTPErrorDetails(t *testing.T) {
+	s := startServer(t, &Config{}, NewServerForTextEncoding(tooLongEncoder{}))
+
+	resp, err := http.Post("http://"+s.ClientAddr()+"/v1/encode", "application/json", strings.NewReader(`{"input": "a"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var body struct {
+		Code    int
+		Message string
+		Details []map[string]any
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, int(codes.InvalidArgument), body.Code)
+	assert.Equal(t, "input sequence too long: 600 > 512", body.Message)
+	require.Len(t, body.Details, 2)
+	assert.Equal(t, "type.googleapis.com/google.rpc.ErrorInfo", body.Details[0]["@type"])
+	assert.Equal(t, ReasonInputSequenceTooLong, body.Details[0]["reason"])
+	assert.Equal(t, map[string]any{"length": "600", "max_length": "512"}, body.Details[0]["metadata"])
+}
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	LanguageModelingService_Predict_FullMethodName = "/languagemodeling.v1.LanguageModelingService/Predict"
+	LanguageModelingService_Predict_FullMethodName      = "/languagemodeling.v1.LanguageModelingService/Predict"
+	LanguageModelingService_BatchPredict_FullMethodName = "/languagemodeling.v1.LanguageModelingService/BatchPredict"
 )
 
 // LanguageModelingServiceClient is the client API for LanguageModelingService service.
@@ -27,6 +28,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LanguageModelingServiceClient interface {
 	Predict(ctx context.Context, in *LanguageModelingRequest, opts ...grpc.CallOption) (*LanguageModelingResponse, error)
+	// BatchPredict predicts the masked tokens of several inputs with the same
+	// parameters, reporting the result or the error of each input.
+	BatchPredict(ctx context.Context, in *BatchLanguageModelingRequest, opts ...grpc.CallOption) (*BatchLanguageModelingResponse, error)
 }
 
 type languageModelingServiceClient struct {
@@ -46,11 +50,23 @@ func (c *languageModelingServiceClient) Predict(ctx context.Context, in *Languag
 	return out, nil
 }
 
+func (c *languageModelingServiceClient) BatchPredict(ctx context.Context, in *BatchLanguageModelingRequest, opts ...grpc.CallOption) (*BatchLanguageModelingResponse, error) {
+	out := new(BatchLanguageModelingResponse)
+	err := c.cc.Invoke(ctx, LanguageModelingService_BatchPredict_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LanguageModelingServiceServer is the server API for LanguageModelingService service.
 // All implementations must embed UnimplementedLanguageModelingServiceServer
 // for forward compatibility
 type LanguageModelingServiceServer interface {
 	Predict(context.Context, *LanguageModelingRequest) (*LanguageModelingResponse, error)
+	// BatchPredict predicts the masked tokens of several inputs with the same
+	// parameters, reporting the result or the error of each input.
+	BatchPredict(context.Context, *BatchLanguageModelingRequest) (*BatchLanguageModelingResponse, error)
 	mustEmbedUnimplementedLanguageModelingServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedLanguageModelingServiceServer struct {
 func (UnimplementedLanguageModelingServiceServer) Predict(context.Context, *LanguageModelingRequest) (*LanguageModelingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Predict not implemented")
 }
+func (UnimplementedLanguageModelingServiceServer) BatchPredict(context.Context, *BatchLanguageModelingRequest) (*BatchLanguageModelingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchPredict not implemented")
+}
 func (UnimplementedLanguageModelingServiceServer) mustEmbedUnimplementedLanguageModelingServiceServer() {
 }
 
@@ -93,6 +112,24 @@ func _LanguageModelingService_Predict_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LanguageModelingService_BatchPredict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchLanguageModelingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LanguageModelingServiceServer).BatchPredict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LanguageModelingService_BatchPredict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LanguageModelingServiceServer).BatchPredict(ctx, req.(*BatchLanguageModelingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LanguageModelingService_ServiceDesc is the grpc.ServiceDesc for LanguageModelingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -104,6 +141,10 @@ var LanguageModelingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Predict",
 			Handler:    _LanguageModelingService_Predict_Handler,
 		},
+		{
+			MethodName: "BatchPredict",
+			Handler:    _LanguageModelingService_BatchPredict_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "languagemodeling/v1/languagemodeling.proto",
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: modeladmin/v1/modeladmin.proto
+
+package modeladminv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ModelAdminService_ListModels_FullMethodName  = "/modeladmin.v1.ModelAdminService/ListModels"
+	ModelAdminService_LoadModel_FullMethodName   = "/modeladmin.v1.ModelAdminService/LoadModel"
+	ModelAdminService_UnloadModel_FullMethodName = "/modeladmin.v1.ModelAdminService/UnloadModel"
+	ModelAdminService_SwapModel_FullMethodName   = "/modeladmin.v1.ModelAdminService/SwapModel"
+)
+
+// ModelAdminServiceClient is the client API for ModelAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ModelAdminServiceClient interface {
+	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
+	LoadModel(ctx context.Context, in *LoadModelRequest, opts ...grpc.CallOption) (*LoadModelResponse, error)
+	UnloadModel(ctx context.Context, in *UnloadModelRequest, opts ...grpc.CallOption) (*UnloadModelResponse, error)
+	SwapModel(ctx context.Context, in *SwapModelRequest, opts ...grpc.CallOption) (*SwapModelResponse, error)
+}
+
+type modelAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewModelAdminServiceClient(cc grpc.ClientConnInterface) ModelAdminServiceClient {
+	return &modelAdminServiceClient{cc}
+}
+
+func (c *modelAdminServiceClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, ModelAdminService_ListModels_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modelAdminServiceClient) LoadModel(ctx context.Context, in *LoadModelRequest, opts ...grpc.CallOption) (*LoadModelResponse, error) {
+	out := new(LoadModelResponse)
+	err := c.cc.Invoke(ctx, ModelAdminService_LoadModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modelAdminServiceClient) UnloadModel(ctx context.Context, in *UnloadModelRequest, opts ...grpc.CallOption) (*UnloadModelResponse, error) {
+	out := new(UnloadModelResponse)
+	err := c.cc.Invoke(ctx, ModelAdminService_UnloadModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *modelAdminServiceClient) SwapModel(ctx context.Context, in *SwapModelRequest, opts ...grpc.CallOption) (*SwapModelResponse, error) {
+	out := new(SwapModelResponse)
+	err := c.cc.Invoke(ctx, ModelAdminService_SwapModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ModelAdminServiceServer is the server API for ModelAdminService service.
+// All implementations must embed UnimplementedModelAdminServiceServer
+// for forward compatibility
+type ModelAdminServiceServer interface {
+	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
+	LoadModel(context.Context, *LoadModelRequest) (*LoadModelResponse, error)
+	UnloadModel(context.Context, *UnloadModelRequest) (*UnloadModelResponse, error)
+	SwapModel(context.Context, *SwapModelRequest) (*SwapModelResponse, error)
+	mustEmbedUnimplementedModelAdminServiceServer()
+}
+
+// UnimplementedModelAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedModelAdminServiceServer struct {
+}
+
+func (UnimplementedModelAdminServiceServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedModelAdminServiceServer) LoadModel(context.Context, *LoadModelRequest) (*LoadModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadModel not implemented")
+}
+func (UnimplementedModelAdminServiceServer) UnloadModel(context.Context, *UnloadModelRequest) (*UnloadModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnloadModel not implemented")
+}
+func (UnimplementedModelAdminServiceServer) SwapModel(context.Context, *SwapModelRequest) (*SwapModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwapModel not implemented")
+}
+func (UnimplementedModelAdminServiceServer) mustEmbedUnimplementedModelAdminServiceServer() {}
+
+// UnsafeModelAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ModelAdminServiceServer will
+// result in compilation errors.
+type UnsafeModelAdminServiceServer interface {
+	mustEmbedUnimplementedModelAdminServiceServer()
+}
+
+func RegisterModelAdminServiceServer(s grpc.ServiceRegistrar, srv ModelAdminServiceServer) {
+	s.RegisterService(&ModelAdminService_ServiceDesc, srv)
+}
+
+func _ModelAdminService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModelAdminServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModelAdminService_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModelAdminServiceServer).ListModels(ctx, req.(*ListModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModelAdminService_LoadModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModelAdminServiceServer).LoadModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModelAdminService_LoadModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModelAdminServiceServer).LoadModel(ctx, req.(*LoadModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModelAdminService_UnloadModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnloadModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModelAdminServiceServer).UnloadModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModelAdminService_UnloadModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModelAdminServiceServer).UnloadModel(ctx, req.(*UnloadModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ModelAdminService_SwapModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModelAdminServiceServer).SwapModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ModelAdminService_SwapModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModelAdminServiceServer).SwapModel(ctx, req.(*SwapModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ModelAdminService_ServiceDesc is the grpc.ServiceDesc for ModelAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ModelAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "modeladmin.v1.ModelAdminService",
+	HandlerType: (*ModelAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListModels",
+			Handler:    _ModelAdminService_ListModels_Handler,
+		},
+		{
+			MethodName: "LoadModel",
+			Handler:    _ModelAdminService_LoadModel_Handler,
+		},
+		{
+			MethodName: "UnloadModel",
+			Handler:    _ModelAdminService_UnloadModel_Handler,
+		},
+		{
+			MethodName: "SwapModel",
+			Handler:    _ModelAdminService_SwapModel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "modeladmin/v1/modeladmin.proto",
+}
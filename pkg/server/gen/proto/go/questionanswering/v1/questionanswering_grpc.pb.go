@@ -19,14 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	QuestionAnsweringService_Answer_FullMethodName = "/questionanswering.v1.QuestionAnsweringService/ExtractAnswer"
+	QuestionAnsweringService_ExtractAnswer_FullMethodName      = "/questionanswering.v1.QuestionAnsweringService/ExtractAnswer"
+	QuestionAnsweringService_BatchExtractAnswer_FullMethodName = "/questionanswering.v1.QuestionAnsweringService/BatchExtractAnswer"
 )
 
 // QuestionAnsweringServiceClient is the client API for QuestionAnsweringService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type QuestionAnsweringServiceClient interface {
-	Answer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	ExtractAnswer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	// BatchExtractAnswer answers several questions with the same options,
+	// reporting the result or the error of each of them.
+	BatchExtractAnswer(ctx context.Context, in *BatchAnswerRequest, opts ...grpc.CallOption) (*BatchAnswerResponse, error)
 }
 
 type questionAnsweringServiceClient struct {
@@ -37,9 +41,18 @@ func NewQuestionAnsweringServiceClient(cc grpc.ClientConnInterface) QuestionAnsw
 	return &questionAnsweringServiceClient{cc}
 }
 
-func (c *questionAnsweringServiceClient) Answer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+func (c *questionAnsweringServiceClient) ExtractAnswer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
 	out := new(AnswerResponse)
-	err := c.cc.Invoke(ctx, QuestionAnsweringService_Answer_FullMethodName, in, out, opts...)
+	err := c.cc.Invoke(ctx, QuestionAnsweringService_ExtractAnswer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *questionAnsweringServiceClient) BatchExtractAnswer(ctx context.Context, in *BatchAnswerRequest, opts ...grpc.CallOption) (*BatchAnswerResponse, error) {
+	out := new(BatchAnswerResponse)
+	err := c.cc.Invoke(ctx, QuestionAnsweringService_BatchExtractAnswer_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +64,9 @@ func (c *questionAnsweringServiceClient) Answer(ctx context.Context, in *AnswerR
 // for forward compatibility
 type QuestionAnsweringServiceServer interface {
 	ExtractAnswer(context.Context, *AnswerRequest) (*AnswerResponse, error)
+	// BatchExtractAnswer answers several questions with the same options,
+	// reporting the result or the error of each of them.
+	BatchExtractAnswer(context.Context, *BatchAnswerRequest) (*BatchAnswerResponse, error)
 	mustEmbedUnimplementedQuestionAnsweringServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedQuestionAnsweringServiceServer struct {
 func (UnimplementedQuestionAnsweringServiceServer) ExtractAnswer(context.Context, *AnswerRequest) (*AnswerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExtractAnswer not implemented")
 }
+func (UnimplementedQuestionAnsweringServiceServer) BatchExtractAnswer(context.Context, *BatchAnswerRequest) (*BatchAnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchExtractAnswer not implemented")
+}
 func (UnimplementedQuestionAnsweringServiceServer) mustEmbedUnimplementedQuestionAnsweringServiceServer() {
 }
 
@@ -75,7 +94,7 @@ func RegisterQuestionAnsweringServiceServer(s grpc.ServiceRegistrar, srv Questio
 	s.RegisterService(&QuestionAnsweringService_ServiceDesc, srv)
 }
 
-func _QuestionAnsweringService_Answer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _QuestionAnsweringService_ExtractAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AnswerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
@@ -85,7 +104,7 @@ func _QuestionAnsweringService_Answer_Handler(srv interface{}, ctx context.Conte
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: QuestionAnsweringService_Answer_FullMethodName,
+		FullMethod: QuestionAnsweringService_ExtractAnswer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(QuestionAnsweringServiceServer).ExtractAnswer(ctx, req.(*AnswerRequest))
@@ -93,6 +112,24 @@ func _QuestionAnsweringService_Answer_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QuestionAnsweringService_BatchExtractAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuestionAnsweringServiceServer).BatchExtractAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QuestionAnsweringService_BatchExtractAnswer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuestionAnsweringServiceServer).BatchExtractAnswer(ctx, req.(*BatchAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QuestionAnsweringService_ServiceDesc is the grpc.ServiceDesc for QuestionAnsweringService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -102,7 +139,11 @@ var QuestionAnsweringService_ServiceDesc = grpc.ServiceDesc{
 	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "ExtractAnswer",
-			Handler:    _QuestionAnsweringService_Answer_Handler,
+			Handler:    _QuestionAnsweringService_ExtractAnswer_Handler,
+		},
+		{
+			MethodName: "BatchExtractAnswer",
+			Handler:    _QuestionAnsweringService_BatchExtractAnswer_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
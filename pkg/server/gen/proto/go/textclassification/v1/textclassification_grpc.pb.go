@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TextClassificationService_Classify_FullMethodName = "/textclassification.v1.TextClassificationService/Classify"
+	TextClassificationService_Classify_FullMethodName      = "/textclassification.v1.TextClassificationService/Classify"
+	TextClassificationService_BatchClassify_FullMethodName = "/textclassification.v1.TextClassificationService/BatchClassify"
 )
 
 // TextClassificationServiceClient is the client API for TextClassificationService service.
@@ -27,6 +28,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TextClassificationServiceClient interface {
 	Classify(ctx context.Context, in *ClassifyRequest, opts ...grpc.CallOption) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs, reporting the result or the
+	// error of each input.
+	BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error)
 }
 
 type textClassificationServiceClient struct {
@@ -46,11 +50,23 @@ func (c *textClassificationServiceClient) Classify(ctx context.Context, in *Clas
 	return out, nil
 }
 
+func (c *textClassificationServiceClient) BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error) {
+	out := new(BatchClassifyResponse)
+	err := c.cc.Invoke(ctx, TextClassificationService_BatchClassify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TextClassificationServiceServer is the server API for TextClassificationService service.
 // All implementations must embed UnimplementedTextClassificationServiceServer
 // for forward compatibility
 type TextClassificationServiceServer interface {
 	Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs, reporting the result or the
+	// error of each input.
+	BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error)
 	mustEmbedUnimplementedTextClassificationServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedTextClassificationServiceServer struct {
 func (UnimplementedTextClassificationServiceServer) Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Classify not implemented")
 }
+func (UnimplementedTextClassificationServiceServer) BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchClassify not implemented")
+}
 func (UnimplementedTextClassificationServiceServer) mustEmbedUnimplementedTextClassificationServiceServer() {
 }
 
@@ -93,6 +112,24 @@ func _TextClassificationService_Classify_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TextClassificationService_BatchClassify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchClassifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextClassificationServiceServer).BatchClassify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TextClassificationService_BatchClassify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextClassificationServiceServer).BatchClassify(ctx, req.(*BatchClassifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TextClassificationService_ServiceDesc is the grpc.ServiceDesc for TextClassificationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -104,6 +141,10 @@ var TextClassificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Classify",
 			Handler:    _TextClassificationService_Classify_Handler,
 		},
+		{
+			MethodName: "BatchClassify",
+			Handler:    _TextClassificationService_BatchClassify_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "textclassification/v1/textclassification.proto",
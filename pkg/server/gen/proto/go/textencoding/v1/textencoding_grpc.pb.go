@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TextEncodingService_Encode_FullMethodName = "/textencoding.v1.TextEncodingService/Encode"
+	TextEncodingService_Encode_FullMethodName      = "/textencoding.v1.TextEncodingService/Encode"
+	TextEncodingService_BatchEncode_FullMethodName = "/textencoding.v1.TextEncodingService/BatchEncode"
 )
 
 // TextEncodingServiceClient is the client API for TextEncodingService service.
@@ -27,6 +28,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TextEncodingServiceClient interface {
 	Encode(ctx context.Context, in *EncodingRequest, opts ...grpc.CallOption) (*EncodingResponse, error)
+	// BatchEncode encodes several inputs with the same parameters, reporting
+	// the result or the error of each input.
+	BatchEncode(ctx context.Context, in *BatchEncodingRequest, opts ...grpc.CallOption) (*BatchEncodingResponse, error)
 }
 
 type textEncodingServiceClient struct {
@@ -46,11 +50,23 @@ func (c *textEncodingServiceClient) Encode(ctx context.Context, in *EncodingRequ
 	return out, nil
 }
 
+func (c *textEncodingServiceClient) BatchEncode(ctx context.Context, in *BatchEncodingRequest, opts ...grpc.CallOption) (*BatchEncodingResponse, error) {
+	out := new(BatchEncodingResponse)
+	err := c.cc.Invoke(ctx, TextEncodingService_BatchEncode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TextEncodingServiceServer is the server API for TextEncodingService service.
 // All implementations must embed UnimplementedTextEncodingServiceServer
 // for forward compatibility
 type TextEncodingServiceServer interface {
 	Encode(context.Context, *EncodingRequest) (*EncodingResponse, error)
+	// BatchEncode encodes several inputs with the same parameters, reporting
+	// the result or the error of each input.
+	BatchEncode(context.Context, *BatchEncodingRequest) (*BatchEncodingResponse, error)
 	mustEmbedUnimplementedTextEncodingServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedTextEncodingServiceServer struct {
 func (UnimplementedTextEncodingServiceServer) Encode(context.Context, *EncodingRequest) (*EncodingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Encode not implemented")
 }
+func (UnimplementedTextEncodingServiceServer) BatchEncode(context.Context, *BatchEncodingRequest) (*BatchEncodingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchEncode not implemented")
+}
 func (UnimplementedTextEncodingServiceServer) mustEmbedUnimplementedTextEncodingServiceServer() {}
 
 // UnsafeTextEncodingServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +111,24 @@ func _TextEncodingService_Encode_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TextEncodingService_BatchEncode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchEncodingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextEncodingServiceServer).BatchEncode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TextEncodingService_BatchEncode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextEncodingServiceServer).BatchEncode(ctx, req.(*BatchEncodingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TextEncodingService_ServiceDesc is the grpc.ServiceDesc for TextEncodingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +140,10 @@ var TextEncodingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Encode",
 			Handler:    _TextEncodingService_Encode_Handler,
 		},
+		{
+			MethodName: "BatchEncode",
+			Handler:    _TextEncodingService_BatchEncode_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "textencoding/v1/textencoding.proto",
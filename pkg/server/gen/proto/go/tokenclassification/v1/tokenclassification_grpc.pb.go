@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TokenClassificationService_Classify_FullMethodName = "/tokenclassification.v1.TokenClassificationService/Classify"
+	TokenClassificationService_Classify_FullMethodName      = "/tokenclassification.v1.TokenClassificationService/Classify"
+	TokenClassificationService_BatchClassify_FullMethodName = "/tokenclassification.v1.TokenClassificationService/BatchClassify"
 )
 
 // TokenClassificationServiceClient is the client API for TokenClassificationService service.
@@ -27,6 +28,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TokenClassificationServiceClient interface {
 	Classify(ctx context.Context, in *ClassifyRequest, opts ...grpc.CallOption) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs with the same parameters,
+	// reporting the result or the error of each input.
+	BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error)
 }
 
 type tokenClassificationServiceClient struct {
@@ -46,11 +50,23 @@ func (c *tokenClassificationServiceClient) Classify(ctx context.Context, in *Cla
 	return out, nil
 }
 
+func (c *tokenClassificationServiceClient) BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error) {
+	out := new(BatchClassifyResponse)
+	err := c.cc.Invoke(ctx, TokenClassificationService_BatchClassify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TokenClassificationServiceServer is the server API for TokenClassificationService service.
 // All implementations must embed UnimplementedTokenClassificationServiceServer
 // for forward compatibility
 type TokenClassificationServiceServer interface {
 	Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs with the same parameters,
+	// reporting the result or the error of each input.
+	BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error)
 	mustEmbedUnimplementedTokenClassificationServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedTokenClassificationServiceServer struct {
 func (UnimplementedTokenClassificationServiceServer) Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Classify not implemented")
 }
+func (UnimplementedTokenClassificationServiceServer) BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchClassify not implemented")
+}
 func (UnimplementedTokenClassificationServiceServer) mustEmbedUnimplementedTokenClassificationServiceServer() {
 }
 
@@ -93,6 +112,24 @@ func _TokenClassificationService_Classify_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TokenClassificationService_BatchClassify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchClassifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenClassificationServiceServer).BatchClassify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenClassificationService_BatchClassify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenClassificationServiceServer).BatchClassify(ctx, req.(*BatchClassifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TokenClassificationService_ServiceDesc is the grpc.ServiceDesc for TokenClassificationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -104,6 +141,10 @@ var TokenClassificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Classify",
 			Handler:    _TokenClassificationService_Classify_Handler,
 		},
+		{
+			MethodName: "BatchClassify",
+			Handler:    _TokenClassificationService_BatchClassify_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "tokenclassification/v1/tokenclassification.proto",
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ZeroShotService_Classify_FullMethodName = "/zeroshot.v1.ZeroShotService/Classify"
+	ZeroShotService_Classify_FullMethodName      = "/zeroshot.v1.ZeroShotService/Classify"
+	ZeroShotService_BatchClassify_FullMethodName = "/zeroshot.v1.ZeroShotService/BatchClassify"
 )
 
 // ZeroShotServiceClient is the client API for ZeroShotService service.
@@ -27,6 +28,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ZeroShotServiceClient interface {
 	Classify(ctx context.Context, in *ClassifyRequest, opts ...grpc.CallOption) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs with the same parameters,
+	// reporting the result or the error of each input.
+	BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error)
 }
 
 type zeroShotServiceClient struct {
@@ -46,11 +50,23 @@ func (c *zeroShotServiceClient) Classify(ctx context.Context, in *ClassifyReques
 	return out, nil
 }
 
+func (c *zeroShotServiceClient) BatchClassify(ctx context.Context, in *BatchClassifyRequest, opts ...grpc.CallOption) (*BatchClassifyResponse, error) {
+	out := new(BatchClassifyResponse)
+	err := c.cc.Invoke(ctx, ZeroShotService_BatchClassify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ZeroShotServiceServer is the server API for ZeroShotService service.
 // All implementations must embed UnimplementedZeroShotServiceServer
 // for forward compatibility
 type ZeroShotServiceServer interface {
 	Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error)
+	// BatchClassify classifies several inputs with the same parameters,
+	// reporting the result or the error of each input.
+	BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error)
 	mustEmbedUnimplementedZeroShotServiceServer()
 }
 
@@ -61,6 +77,9 @@ type UnimplementedZeroShotServiceServer struct {
 func (UnimplementedZeroShotServiceServer) Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Classify not implemented")
 }
+func (UnimplementedZeroShotServiceServer) BatchClassify(context.Context, *BatchClassifyRequest) (*BatchClassifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchClassify not implemented")
+}
 func (UnimplementedZeroShotServiceServer) mustEmbedUnimplementedZeroShotServiceServer() {}
 
 // UnsafeZeroShotServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +111,24 @@ func _ZeroShotService_Classify_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ZeroShotService_BatchClassify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchClassifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZeroShotServiceServer).BatchClassify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ZeroShotService_BatchClassify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZeroShotServiceServer).BatchClassify(ctx, req.(*BatchClassifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ZeroShotService_ServiceDesc is the grpc.ServiceDesc for ZeroShotService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +140,10 @@ var ZeroShotService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Classify",
 			Handler:    _ZeroShotService_Classify_Handler,
 		},
+		{
+			MethodName: "BatchClassify",
+			Handler:    _ZeroShotService_BatchClassify_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "zeroshot/v1/zeroshot.proto",
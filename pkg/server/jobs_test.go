@@ -0,0 +1,278 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jobsv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/jobs/v1"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	textgenerationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textgeneration/v1"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// blockingGenerator generates until its context is done, as the beam search
+// decoder does, unless released.
+type blockingGenerator struct {
+	textgeneration.Interface
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *blockingGenerator) Generate(ctx context.Context, text string, _ *textgeneration.Options) (textgeneration.Response, error) {
+	g.started <- struct{}{}
+	select {
+	case <-g.release:
+		return textgeneration.Response{Texts: []string{text}, Scores: []float64{1}}, nil
+	case <-ctx.Done():
+		return textgeneration.Response{}, ctx.Err()
+	}
+}
+
+func newJobsTestServer(t *testing.T, conf JobsConfig) (*serverForJobs, *blockingGenerator) {
+	t.Helper()
+	g := &blockingGenerator{started: make(chan struct{}, 10), release: make(chan struct{})}
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+	require.NoError(t, r.Add("generator", g))
+	s, err := NewServerForJobs(r, conf)
+	require.NoError(t, err)
+	return s.(*serverForJobs), g
+}
+
+func submitJob(t *testing.T, s *serverForJobs, method string, req proto.Message, callbackURL string) (*jobsv1.Job, error) {
+	t.Helper()
+	packed, err := anypb.New(req)
+	require.NoError(t, err)
+	return s.SubmitJob(context.Background(), &jobsv1.SubmitJobRequest{Method: method, Request: packed, CallbackUrl: callbackURL})
+}
+
+// waitJob polls the job until it is done.
+func waitJob(t *testing.T, s *serverForJobs, id string) *jobsv1.Job {
+	t.Helper()
+	var job *jobsv1.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = s.GetJob(context.Background(), &jobsv1.GetJobRequest{Id: id})
+		return err == nil && job.GetEndTime() != nil
+	}, 5*time.Second, time.Millisecond)
+	return job
+}
+
+func TestServerForJobs(t *testing.T) {
+	ctx := context.Background()
+	s, g := newJobsTestServer(t, JobsConfig{Workers: 1, MaxQueueLength: 1})
+
+	t.Run("success", func(t *testing.T) {
+		job, err := submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{Input: "a"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, jobsv1.Job_PENDING, job.GetState())
+
+		job = waitJob(t, s, job.GetId())
+		assert.Equal(t, jobsv1.Job_SUCCEEDED, job.GetState())
+		resp := &textencodingv1.EncodingResponse{}
+		require.NoError(t, job.GetResponse().UnmarshalTo(resp))
+		assert.Equal(t, []float32{1, 2}, resp.GetVector())
+		assert.True(t, job.GetExpireTime().AsTime().After(job.GetEndTime().AsTime()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := submitJob(t, s, "/textencoding.v1.TextEncodingService/Unknown", &textencodingv1.EncodingRequest{}, "")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = submitJob(t, s, textgenerationv1.TextGenerationService_Generate_FullMethodName, &textencodingv1.EncodingRequest{}, "")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "request type of another method")
+		_, err = submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{}, "ftp://example.com")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "callback URL")
+		_, err = submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{}, "http://example.com")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "callbacks disabled")
+		_, err = s.GetJob(ctx, &jobsv1.GetJobRequest{Id: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("cancel and queue full", func(t *testing.T) {
+		generate := &textgenerationv1.GenerateRequest{Input: "hello"}
+		running, err := submitJob(t, s, textgenerationv1.TextGenerationService_Generate_FullMethodName, generate, "")
+		require.NoError(t, err)
+		<-g.started
+
+		pending, err := submitJob(t, s, textgenerationv1.TextGenerationService_Generate_FullMethodName, generate, "")
+		require.NoError(t, err)
+		_, err = submitJob(t, s, textgenerationv1.TextGenerationService_Generate_FullMethodName, generate, "")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		list, err := s.ListJobs(ctx, &jobsv1.ListJobsRequest{State: jobsv1.Job_RUNNING})
+		require.NoError(t, err)
+		require.Len(t, list.GetJobs(), 1)
+		assert.Equal(t, running.GetId(), list.GetJobs()[0].GetId())
+
+		job, err := s.CancelJob(ctx, &jobsv1.CancelJobRequest{Id: pending.GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, jobsv1.Job_CANCELED, job.GetState(), "pending jobs are canceled right away")
+
+		_, err = s.CancelJob(ctx, &jobsv1.CancelJobRequest{Id: running.GetId()})
+		require.NoError(t, err)
+		job = waitJob(t, s, running.GetId())
+		assert.Equal(t, jobsv1.Job_CANCELED, job.GetState())
+		assert.Equal(t, int32(codes.Canceled), job.GetError().GetCode())
+	})
+}
+
+func TestServerForJobs_Owner(t *testing.T) {
+	s, _ := newJobsTestServer(t, JobsConfig{})
+	alice := context.WithValue(context.Background(), apiClientKey{}, &apiClient{name: "alice"})
+	bob := context.WithValue(context.Background(), apiClientKey{}, &apiClient{name: "bob"})
+
+	packed, err := anypb.New(&textencodingv1.EncodingRequest{Input: "a"})
+	require.NoError(t, err)
+	job, err := s.SubmitJob(alice, &jobsv1.SubmitJobRequest{Method: textencodingv1.TextEncodingService_Encode_FullMethodName, Request: packed})
+	require.NoError(t, err)
+
+	_, err = s.GetJob(alice, &jobsv1.GetJobRequest{Id: job.GetId()})
+	assert.NoError(t, err)
+	_, err = s.GetJob(bob, &jobsv1.GetJobRequest{Id: job.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err), "job of another client")
+	_, err = s.CancelJob(bob, &jobsv1.CancelJobRequest{Id: job.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err), "job of another client")
+
+	list, err := s.ListJobs(bob, &jobsv1.ListJobsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.GetJobs())
+	list, err = s.ListJobs(alice, &jobsv1.ListJobsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetJobs(), 1)
+	assert.Equal(t, job.GetId(), list.GetJobs()[0].GetId())
+}
+
+func TestServerForJobs_TTL(t *testing.T) {
+	s, _ := newJobsTestServer(t, JobsConfig{TTL: time.Minute})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	job, err := submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{Input: "a"}, "")
+	require.NoError(t, err)
+	waitJob(t, s, job.GetId())
+
+	now = now.Add(2 * time.Minute)
+	_, err = s.GetJob(context.Background(), &jobsv1.GetJobRequest{Id: job.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerForJobs_Callback(t *testing.T) {
+	jobs := make(chan *jobsv1.Job, 1)
+	failures := 1
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		job := &jobsv1.Job{}
+		assert.NoError(t, protojson.Unmarshal(body, job))
+		jobs <- job
+	}))
+	defer callback.Close()
+
+	s, _ := newJobsTestServer(t, JobsConfig{CallbackHosts: []string{"example.com", "127.0.0.1"}})
+	// the callback server listens on loopback, which the callback client refuses
+	s.callbacks = &http.Client{}
+	_, err := submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{Input: "a"}, "http://localhost/")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "host not allowed")
+	submitted, err := submitJob(t, s, textencodingv1.TextEncodingService_Encode_FullMethodName, &textencodingv1.EncodingRequest{Input: "a"}, callback.URL)
+	require.NoError(t, err)
+
+	select {
+	case job := <-jobs:
+		assert.Equal(t, submitted.GetId(), job.GetId())
+		assert.Equal(t, jobsv1.Job_SUCCEEDED, job.GetState())
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestServerForJobs_CallbackCanceled(t *testing.T) {
+	var requests atomic.Int32
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer callback.Close()
+
+	s, _ := newJobsTestServer(t, JobsConfig{})
+	s.callbacks = &http.Client{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.callBack(ctx, &jobsv1.Job{Id: "job", CallbackUrl: callback.URL})
+		close(done)
+	}()
+	select {
+	case <-done:
+		assert.Zero(t, requests.Load())
+	case <-time.After(time.Second):
+		t.Fatal("callback retried after the server context is done")
+	}
+}
+
+func TestCallbackDialControl(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:80", "10.0.0.1:80", "192.168.1.1:443", "169.254.169.254:80", "[fe80::1]:80", "0.0.0.0:80", "[::ffff:127.0.0.1]:80"} {
+		assert.Error(t, callbackDialControl("tcp", address, nil), address)
+	}
+	for _, address := range []string{"93.184.216.34:80", "[2606:2800:220:1:248:1893:25c8:1946]:443"} {
+		assert.NoError(t, callbackDialControl("tcp", address, nil), address)
+	}
+}
+
+func TestServer_Jobs(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+	jobs, err := NewServerForJobs(r, JobsConfig{})
+	require.NoError(t, err)
+	s := startServer(t, &Config{}, JoinRequestHandlers(r, jobs))
+	baseURL := "http://" + s.ClientAddr()
+
+	resp, err := http.Post(baseURL+"/v1/jobs", "application/json", strings.NewReader(`{
+		"method": "/textencoding.v1.TextEncodingService/Encode",
+		"request": {"@type": "type.googleapis.com/textencoding.v1.EncodingRequest", "input": "a"}
+	}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var submitted struct{ ID string }
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+	require.NotEmpty(t, submitted.ID)
+
+	var job struct {
+		State    string
+		Response struct{ Vector []float32 }
+	}
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(baseURL + "/v1/jobs/" + submitted.ID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+		return job.State == "SUCCEEDED"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []float32{1, 2}, job.Response.Vector)
+}
@@ -0,0 +1,379 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metricsPath is the HTTP path serving the metrics in the Prometheus text format.
+const metricsPath = "/metrics"
+
+// metrics collects the metrics of the server. It implements
+// instrument.Observer to collect the measurements reported by the tasks.
+type metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	inFlight     *prometheus.GaugeVec
+	inputTokens  *prometheus.HistogramVec
+	tokenization *prometheus.HistogramVec
+	forward      *prometheus.HistogramVec
+	batchSize    *prometheus.HistogramVec
+	batchWait    *prometheus.HistogramVec
+	cache        *prometheus.CounterVec
+	// shadowRouted, shadowRequests, shadowLabels, shadowJaccard and
+	// shadowCosine are the metrics of the shadow deployments.
+	shadowRouted   *prometheus.CounterVec
+	shadowRequests *prometheus.CounterVec
+	shadowLabels   *prometheus.CounterVec
+	shadowJaccard  *prometheus.HistogramVec
+	shadowCosine   *prometheus.HistogramVec
+}
+
+var _ instrument.Observer = &metrics{}
+
+// modelLister is implemented by the request handlers serving several models,
+// such as the Registry.
+type modelLister interface {
+	Models() []ModelInfo
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cybertron_requests_total",
+			Help: "Total number of requests, by service, method and status code.",
+		}, []string{"protocol", "service", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_request_duration_seconds",
+			Help:    "Duration of the requests, by service, method and status code.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		}, []string{"protocol", "service", "method", "code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cybertron_requests_in_flight",
+			Help: "Number of requests currently being served, by service and method.",
+		}, []string{"protocol", "service", "method"}),
+		inputTokens: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_input_tokens",
+			Help:    "Number of tokens of the input sequences, by task.",
+			Buckets: prometheus.ExponentialBuckets(8, 2, 10),
+		}, []string{"task"}),
+		tokenization: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_tokenization_duration_seconds",
+			Help:    "Time spent tokenizing the inputs, by task.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, []string{"task"}),
+		forward: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_forward_duration_seconds",
+			Help:    "Time spent running the models, by task.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		}, []string{"task"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_batch_size",
+			Help:    "Number of requests run together by dynamic batching, by task.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+		}, []string{"task"}),
+		batchWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_batch_wait_seconds",
+			Help:    "Time the batches waited in queue for requests to join them, by task.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 2, 12),
+		}, []string{"task"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cybertron_cache_requests_total",
+			Help: "Total number of lookups in the response cache, by task and result (hit or miss).",
+		}, []string{"task", "result"}),
+		shadowRouted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cybertron_shadow_routed_requests_total",
+			Help: "Total number of requests of the shadow deployments, by service, method and variant serving them (primary or candidate).",
+		}, []string{"service", "method", "variant"}),
+		shadowRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cybertron_shadow_requests_total",
+			Help: "Total number of requests mirrored to the candidates of the shadow deployments, by service, method and result (compared, failed or dropped).",
+		}, []string{"service", "method", "result"}),
+		shadowLabels: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cybertron_shadow_label_comparisons_total",
+			Help: "Total number of top labels of the primary and the candidate compared, by service, method and result (match or mismatch).",
+		}, []string{"service", "method", "result"}),
+		shadowJaccard: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_shadow_entity_jaccard",
+			Help:    "Jaccard similarity of the entities found by the primary and the candidate, by service and method.",
+			Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+		}, []string{"service", "method"}),
+		shadowCosine: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cybertron_shadow_embedding_cosine",
+			Help:    "Cosine similarity of the embeddings of the primary and the candidate, by service and method.",
+			Buckets: []float64{0, 0.5, 0.8, 0.9, 0.95, 0.98, 0.99, 0.995, 0.999, 1},
+		}, []string{"service", "method"}),
+	}
+	m.registry.MustRegister(
+		m.requests, m.duration, m.inFlight,
+		m.inputTokens, m.tokenization, m.forward,
+		m.batchSize, m.batchWait, m.cache,
+		m.shadowRouted, m.shadowRequests, m.shadowLabels, m.shadowJaccard, m.shadowCosine,
+	)
+	m.registerProcessMetrics()
+	return m
+}
+
+// registerProcessMetrics registers the resource usage of the process and host.
+func (m *metrics) registerProcessMetrics() {
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to collect process metrics")
+		return
+	}
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_process_resident_memory_bytes",
+			Help: "Resident memory size of the process in bytes.",
+		}, func() float64 {
+			info, err := p.MemoryInfo()
+			if err != nil {
+				return 0
+			}
+			return float64(info.RSS)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "cybertron_process_cpu_seconds_total",
+			Help: "Total user and system CPU time spent by the process in seconds.",
+		}, func() float64 {
+			times, err := p.Times()
+			if err != nil {
+				return 0
+			}
+			return times.User + times.System
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_process_cpu_percent",
+			Help: "Average CPU usage of the process since its start, in percent.",
+		}, func() float64 {
+			percent, err := p.CPUPercent()
+			if err != nil {
+				return 0
+			}
+			return percent
+		}),
+	)
+	if vmStat, err := mem.VirtualMemory(); err == nil {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_host_memory_total_bytes",
+			Help: "Total memory of the host in bytes.",
+		}, func() float64 { return float64(vmStat.Total) }))
+	}
+	if cpus, err := cpu.Counts(false); err == nil {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_host_cpus",
+			Help: "Number of physical CPU cores of the host.",
+		}, func() float64 { return float64(cpus) }))
+	}
+}
+
+// registerAdmission registers the number of inferences in progress and of
+// the requests waiting for their turn, if the concurrency is limited.
+func (m *metrics) registerAdmission(a *admission) {
+	if a.scheduler == nil {
+		return
+	}
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_inferences_in_progress",
+			Help: "Number of inferences currently running.",
+		}, func() float64 { return float64(a.scheduler.inProgress()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cybertron_inference_queue_length",
+			Help: "Number of requests waiting for an inference slot.",
+		}, func() float64 { return float64(a.scheduler.queued()) }),
+	)
+}
+
+// registerModels registers the number of in-flight requests of each model.
+func (m *metrics) registerModels(models modelLister) {
+	m.registry.MustRegister(&modelsCollector{
+		desc: prometheus.NewDesc(
+			"cybertron_model_in_flight_requests",
+			"Number of requests currently being served, by model.",
+			[]string{"model", "task"}, nil,
+		),
+		models: models,
+	})
+}
+
+// modelsCollector is a prometheus.Collector reporting the in-flight requests of each model.
+type modelsCollector struct {
+	desc   *prometheus.Desc
+	models modelLister
+}
+
+func (c *modelsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *modelsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, info := range c.models.Models() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(info.InFlight), info.Name, info.Task)
+	}
+}
+
+// handler returns the HTTP handler serving the metrics.
+func (m *metrics) handler() runtime.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		h.ServeHTTP(w, r)
+	}
+}
+
+func (m *metrics) ObserveInputTokens(task string, count int) {
+	m.inputTokens.WithLabelValues(task).Observe(float64(count))
+}
+
+func (m *metrics) ObserveTokenization(task string, d time.Duration) {
+	m.tokenization.WithLabelValues(task).Observe(d.Seconds())
+}
+
+func (m *metrics) ObserveForward(task string, d time.Duration) {
+	m.forward.WithLabelValues(task).Observe(d.Seconds())
+}
+
+func (m *metrics) ObserveBatch(task string, size int, wait time.Duration) {
+	m.batchSize.WithLabelValues(task).Observe(float64(size))
+	m.batchWait.WithLabelValues(task).Observe(wait.Seconds())
+}
+
+func (m *metrics) ObserveCache(task string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cache.WithLabelValues(task, result).Inc()
+}
+
+// begin marks the beginning of a request, returning the function to call
+// with the status code once the request has been served.
+func (m *metrics) begin(protocol, fullMethod string) func(code codes.Code) {
+	service, method := splitFullMethod(fullMethod)
+	inFlight := m.inFlight.WithLabelValues(protocol, service, method)
+	inFlight.Inc()
+	start := time.Now()
+
+	return func(code codes.Code) {
+		inFlight.Dec()
+		m.requests.WithLabelValues(protocol, service, method, code.String()).Inc()
+		m.duration.WithLabelValues(protocol, service, method, code.String()).Observe(time.Since(start).Seconds())
+	}
+}
+
+// splitFullMethod splits a gRPC full method name ("/package.Service/Method")
+// into service and method.
+func splitFullMethod(fullMethod string) (service, method string) {
+	service, method, _ = strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+// unaryServerInterceptor returns a gRPC interceptor recording the metrics of unary calls.
+func (m *metrics) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		end := m.begin("grpc", info.FullMethod)
+		resp, err := handler(instrument.WithObserver(ctx, m), req)
+		end(status.Code(err))
+		return resp, err
+	}
+}
+
+// streamServerInterceptor returns a gRPC interceptor recording the metrics of streaming calls.
+func (m *metrics) streamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		end := m.begin("grpc", info.FullMethod)
+		err := handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: instrument.WithObserver(ss.Context(), m)})
+		end(status.Code(err))
+		return err
+	}
+}
+
+// serverStreamWithContext is a grpc.ServerStream overriding the context.
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// httpCall tracks an HTTP request served by the gateway, from when the RPC
+// method is resolved to when the response is written. The status code is OK
+// unless an error is reported.
+type httpCall struct {
+	mu   sync.Mutex
+	end  func(codes.Code)
+	code codes.Code
+}
+
+type httpCallKey struct{}
+
+// httpMiddleware returns an HTTP handler recording the metrics of the
+// requests served by the gateway.
+//
+// The method is known only once the gateway has routed the request: the
+// serveMuxOption hook completes the httpCall placed in the context.
+func (m *metrics) httpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := &httpCall{}
+		ctx := context.WithValue(instrument.WithObserver(r.Context(), m), httpCallKey{}, call)
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		call.mu.Lock()
+		defer call.mu.Unlock()
+		if call.end != nil {
+			call.end(call.code)
+		}
+	})
+}
+
+// serveMuxOption returns the option of the gateway mux completing the
+// httpCall of each request with its RPC method. The status code is set by
+// httpErrorHandler.
+func (m *metrics) serveMuxOption() runtime.ServeMuxOption {
+	return runtime.WithMetadata(func(ctx context.Context, _ *http.Request) metadata.MD {
+		call, ok := ctx.Value(httpCallKey{}).(*httpCall)
+		method, hasMethod := runtime.RPCMethod(ctx)
+		if ok && hasMethod {
+			call.mu.Lock()
+			if call.end == nil {
+				call.end = m.begin("http", method)
+			}
+			call.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+// setHTTPCallCode sets the status code of the httpCall in the context, if any.
+func setHTTPCallCode(ctx context.Context, code codes.Code) {
+	if call, ok := ctx.Value(httpCallKey{}).(*httpCall); ok {
+		call.mu.Lock()
+		call.code = code
+		call.mu.Unlock()
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type instrumentedEncoder struct {
+	fakeEncoder
+}
+
+func (e instrumentedEncoder) Encode(ctx context.Context, text string, _ int) (textencoding.Response, error) {
+	instrument.InputTokens(ctx, "text-encoding", len(strings.Fields(text)))
+	_, span := instrument.Start(ctx, "text-encoding", instrument.Forward)
+	span.End()
+	return textencoding.Response{Vector: mat.NewDense[float32](mat.WithBacking([]float32{1, 2}))}, nil
+}
+
+// startServer starts the server on a random local port, stopping it at the
+// end of the test.
+func startServer(t *testing.T, conf *Config, handler RequestHandler) *Server {
+	t.Helper()
+	conf.Address = "127.0.0.1:0"
+	s := New(conf, handler)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	require.True(t, s.ReadyForConnections(5*time.Second))
+	return s
+}
+
+func TestServer_Metrics(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+
+	s := startServer(t, &Config{MetricsEnabled: true}, r)
+	ctx := context.Background()
+	baseURL := "http://" + s.ClientAddr()
+
+	resp, err := http.Post(baseURL+"/v1/encode", "application/json", strings.NewReader(`{"input": "a b c"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(baseURL+"/v1/encode", "application/json", strings.NewReader(`{"input": "a", "model": "missing"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = textencodingv1.NewTextEncodingServiceClient(conn).Encode(ctx, &textencodingv1.EncodingRequest{Input: "a b"})
+	require.NoError(t, err)
+
+	resp, err = http.Get(baseURL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	metrics := string(body)
+
+	for _, want := range []string{
+		`cybertron_requests_total{code="OK",method="Encode",protocol="http",service="textencoding.v1.TextEncodingService"} 1`,
+		`cybertron_requests_total{code="NotFound",method="Encode",protocol="http",service="textencoding.v1.TextEncodingService"} 1`,
+		`cybertron_requests_total{code="OK",method="Encode",protocol="grpc",service="textencoding.v1.TextEncodingService"} 1`,
+		`cybertron_request_duration_seconds_count{code="OK",method="Encode",protocol="grpc",service="textencoding.v1.TextEncodingService"} 1`,
+		`cybertron_requests_in_flight{method="Encode",protocol="http",service="textencoding.v1.TextEncodingService"} 0`,
+		`cybertron_input_tokens_sum{task="text-encoding"} 5`,
+		`cybertron_forward_duration_seconds_count{task="text-encoding"} 2`,
+		`cybertron_model_in_flight_requests{model="embeddings",task="text-encoding"} 0`,
+		`cybertron_process_resident_memory_bytes`,
+		`cybertron_process_cpu_seconds_total`,
+	} {
+		assert.Contains(t, metrics, want)
+	}
+}
+
+func TestServer_MetricsDisabled(t *testing.T) {
+	s := startServer(t, &Config{}, NewServerForTextEncoding(fakeEncoder{}))
+
+	resp, err := http.Get("http://" + s.ClientAddr() + "/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "disabled by default")
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
+	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// livenessPath is the HTTP path reporting whether the server is alive.
+	livenessPath = "/livez"
+	// readinessPath is the HTTP path reporting whether the server is ready
+	// to serve requests.
+	readinessPath = "/readyz"
+)
+
+// readiness tracks whether the server is ready to serve requests, reporting
+// it on the health server as the status of the whole server.
+type readiness struct {
+	ready  atomic.Bool
+	health *health.Server
+}
+
+func newReadiness(ready bool, h *health.Server) *readiness {
+	r := &readiness{health: h}
+	r.ready.Store(ready)
+	if !ready {
+		h.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+	return r
+}
+
+// setReady marks the server as ready. The status reported on the health
+// server is not changed once it has been shut down.
+func (r *readiness) setReady() {
+	r.ready.Store(true)
+	r.health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+}
+
+// check returns an Unavailable error if the server is not ready.
+func (r *readiness) check() error {
+	if r.ready.Load() {
+		return nil
+	}
+	return withDetails(status.New(codes.Unavailable, "server not ready: models are still loading"),
+		&errdetails.ErrorInfo{Reason: ReasonNotReady, Domain: ErrorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+}
+
+// isInfrastructurePath reports whether the HTTP path is served by the server
+// itself, such as the metrics, the probes and the documentation, rather than
+// by a task.
+func isInfrastructurePath(path string) bool {
+	switch path {
+	case metricsPath, livenessPath, readinessPath, openAPIPath, docsPath:
+		return true
+	default:
+		return false
+	}
+}
+
+// readinessUnaryServerInterceptor returns a gRPC interceptor failing the
+// unary calls while the server is not ready.
+func readinessUnaryServerInterceptor(r *readiness) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isInfrastructureMethod(info.FullMethod) {
+			if err := r.check(); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// readinessStreamServerInterceptor returns a gRPC interceptor failing the
+// streaming calls while the server is not ready.
+func readinessStreamServerInterceptor(r *readiness) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isInfrastructureMethod(info.FullMethod) {
+			if err := r.check(); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// readinessMiddleware returns an HTTP handler failing the requests served by
+// the gateway while the server is not ready.
+func readinessMiddleware(rd *readiness, mux *runtime.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isInfrastructurePath(r.URL.Path) {
+			if err := rd.check(); err != nil {
+				writeHTTPError(mux, w, r, err)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// livenessHandler serves livenessPath, succeeding as long as the server
+// handles requests, even while the models are loading.
+func livenessHandler(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// readinessHandler serves readinessPath, reporting the serving status of the
+// whole server on the health server: it fails with 503 Service Unavailable
+// while the models are loading and once the server is shutting down.
+func (r *readiness) readinessHandler(w http.ResponseWriter, req *http.Request, _ map[string]string) {
+	st := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if resp, err := r.health.Check(req.Context(), &grpc_health_v1.HealthCheckRequest{}); err == nil {
+		st = resp.GetStatus()
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if st != grpc_health_v1.HealthCheckResponse_SERVING {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = fmt.Fprintln(w, st)
+}
+
+// Warmup runs an inference of the model on the given input, so that the
+// first requests do not pay for the lazy initializations. The input is used
+// as every text of the request, e.g. both as question and passage.
+func Warmup(ctx context.Context, model any, input string) error {
+	var err error
+	// same precedence as ResolveRequestHandler
+	switch m := model.(type) {
+	case textgeneration.Interface:
+		_, err = m.Generate(ctx, input, nil)
+	case zeroshotclassifier.Interface:
+		_, err = m.Classify(ctx, input, zeroshotclassifier.Parameters{CandidateLabels: []string{"positive", "negative"}})
+	case questionanswering.Interface:
+		_, err = m.ExtractAnswer(ctx, input, input, nil)
+	case textclassification.Interface:
+		_, err = m.Classify(ctx, input)
+	case textencoding.Interface:
+		_, err = m.Encode(ctx, input, 0)
+	case tokenclassification.Interface:
+		_, err = m.Classify(ctx, input, tokenclassification.Parameters{})
+	case languagemodeling.Interface:
+		_, err = m.Predict(ctx, input, languagemodeling.Parameters{})
+	default:
+		return fmt.Errorf("unsupported model/task type %T", model)
+	}
+	if err != nil {
+		return fmt.Errorf("warmup inference failed: %w", err)
+	}
+	return nil
+}
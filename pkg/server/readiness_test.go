@@ -0,0 +1,73 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_Readiness(t *testing.T) {
+	r := NewRegistry()
+	s := startServer(t, &Config{StartNotReady: true, APIKeys: []APIKey{{Key: "secret"}}}, r)
+	ctx := context.Background()
+	baseURL := "http://" + s.ClientAddr()
+
+	conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+	servingStatus := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+		resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+	probe := func(path string) int {
+		resp, err := http.Get(baseURL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	encoder := client.NewClientForTextEncoding(s.ClientAddr(), client.Options{APIKey: "secret"})
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, servingStatus())
+	assert.Equal(t, http.StatusOK, probe("/livez"), "probes are not authenticated")
+	assert.Equal(t, http.StatusServiceUnavailable, probe("/readyz"))
+	_, err = encoder.Encode(ctx, "a", 0)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/encode", strings.NewReader(`{"input": "a"}`))
+	require.NoError(t, err)
+	req.Header.Set(APIKeyMetadataKey, "secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+	s.SetReady()
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, servingStatus())
+	assert.Equal(t, http.StatusOK, probe("/readyz"))
+	_, err = encoder.Encode(ctx, "a", 0)
+	assert.NoError(t, err)
+}
+
+func TestWarmup(t *testing.T) {
+	assert.NoError(t, Warmup(context.Background(), fakeEncoder{}, "warmup"))
+	assert.NoError(t, Warmup(context.Background(), fakeClassifier{}, "warmup"))
+	assert.Error(t, Warmup(context.Background(), struct{}{}, "warmup"))
+}
@@ -0,0 +1,481 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
+	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ModelMetadataKey is the gRPC metadata key used to select the model serving
+// a request, as an alternative to the "model" field of the request message.
+// Over HTTP, it can be set with the "Grpc-Metadata-Cybertron-Model" header.
+const ModelMetadataKey = "cybertron-model"
+
+// modelRequest is a request message, naming the model to serve it.
+type modelRequest interface {
+	proto.Message
+	GetModel() string
+}
+
+// modelResolver returns the model serving a request, given the name of the
+// model set in the request message (possibly empty), once the request has
+// been admitted (see admission). The returned release function must be
+// called once the request has been served.
+type modelResolver[T any] func(ctx context.Context, req modelRequest) (T, func(), error)
+
+// singleModel returns a modelResolver always resolving to the given model.
+func singleModel[T any](model T) modelResolver[T] {
+	return func(ctx context.Context, req modelRequest) (T, func(), error) {
+		done, err := admit(ctx, req)
+		if err != nil {
+			var zero T
+			return zero, nil, err
+		}
+		return model, done, nil
+	}
+}
+
+// Registry is a RequestHandler serving several named models from a single
+// server. Each model fulfills its own task; models sharing the same task are
+// served by the same service, and requests are routed by model name.
+//
+// A request without a model name is routed to the first model added for the
+// requested task.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[string]*registeredModel
+	// names lists the models in the order they were added.
+	names  []string
+	health *health.Server
+}
+
+var (
+	// ErrModelNotFound means that no model is registered with the given name.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrModelExists means that a model is already registered with the given name.
+	ErrModelExists = errors.New("model already exists")
+	// ErrModelDraining means that a model was removed or replaced, but the
+	// context was done before its in-flight requests were served. The
+	// removal or replacement is applied nonetheless, and the model is closed
+	// once drained. The error also wraps the one of the context.
+	ErrModelDraining = errors.New("model still draining")
+)
+
+// ModelInfo describes a model of the registry.
+type ModelInfo struct {
+	// Name is the name under which the model is served.
+	Name string
+	// Task is the task fulfilled by the model (see TaskOf).
+	Task string
+	// InFlight is the number of requests the model is currently serving.
+	InFlight int64
+	// Version is the version of the model, such as its name on the Hugging
+	// Face Hub, if known.
+	Version string
+}
+
+// registeredModel is a model of the registry, tracking the requests it is serving.
+type registeredModel struct {
+	model    any
+	version  string
+	inFlight atomic.Int64
+	wg       sync.WaitGroup
+}
+
+// info returns the information about the model, registered under the name.
+func (m *registeredModel) info(name string) ModelInfo {
+	return ModelInfo{Name: name, Task: TaskOf(m.model), InFlight: m.inFlight.Load(), Version: m.version}
+}
+
+// acquire marks the beginning of a request, returning the function to call
+// when the request has been served.
+func (m *registeredModel) acquire() func() {
+	m.inFlight.Add(1)
+	m.wg.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.inFlight.Add(-1)
+			m.wg.Done()
+		})
+	}
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		models: make(map[string]*registeredModel),
+	}
+}
+
+// Add registers the model under the given name.
+func (r *Registry) Add(name string, model any) error {
+	return r.AddVersion(name, "", model)
+}
+
+// AddVersion registers the model under the given name, along with its
+// version, such as its name on the Hugging Face Hub.
+func (r *Registry) AddVersion(name, version string, model any) error {
+	if name == "" {
+		return fmt.Errorf("invalid empty model name")
+	}
+	if TaskOf(model) == "" {
+		return fmt.Errorf("unsupported model/task type %T", model)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.models[name]; exists {
+		return fmt.Errorf("%w: %q", ErrModelExists, name)
+	}
+	r.models[name] = &registeredModel{model: model, version: version}
+	r.names = append(r.names, name)
+	r.setServingStatus(name, grpc_health_v1.HealthCheckResponse_SERVING)
+	return nil
+}
+
+// Remove unregisters the named model. New requests are no longer routed to
+// the model, while the in-flight ones are drained: Remove waits until they
+// have been served, or until the context is done, returning ErrModelDraining
+// in the latter case, although the model is removed.
+//
+// Once drained, the model is closed if it implements io.Closer.
+func (r *Registry) Remove(ctx context.Context, name string) error {
+	r.mu.Lock()
+	m, ok := r.models[name]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrModelNotFound, name)
+	}
+	delete(r.models, name)
+	r.names = slices.DeleteFunc(r.names, func(n string) bool { return n == name })
+	r.setServingStatus(name, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	r.mu.Unlock()
+
+	return drain(ctx, m)
+}
+
+// Swap replaces the named model with a new one for the same task. New
+// requests are immediately routed to the new model, while the in-flight
+// ones on the old model are drained: Swap waits until they have been served,
+// or until the context is done, returning ErrModelDraining in the latter
+// case, although the model is replaced.
+//
+// Once drained, the old model is closed if it implements io.Closer.
+func (r *Registry) Swap(ctx context.Context, name string, model any) error {
+	return r.SwapVersion(ctx, name, "", model)
+}
+
+// SwapVersion works like Swap, setting the version of the new model, such as
+// its name on the Hugging Face Hub.
+func (r *Registry) SwapVersion(ctx context.Context, name, version string, model any) error {
+	r.mu.Lock()
+	old, ok := r.models[name]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrModelNotFound, name)
+	}
+	if oldTask, newTask := TaskOf(old.model), TaskOf(model); oldTask != newTask {
+		r.mu.Unlock()
+		return fmt.Errorf("cannot swap model %q for task %q with a model for task %q", name, oldTask, newTask)
+	}
+	r.models[name] = &registeredModel{model: model, version: version}
+	r.mu.Unlock()
+
+	return drain(ctx, old)
+}
+
+// drain waits until the model has served all its in-flight requests, or
+// until the context is done, returning ErrModelDraining. In any case, the
+// model is closed once drained.
+func drain(ctx context.Context, m *registeredModel) error {
+	drained := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(drained)
+		if c, ok := m.model.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Err(err).Msg("failed to close model")
+			}
+		}
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: still serving %d requests: %w", ErrModelDraining, m.inFlight.Load(), ctx.Err())
+	}
+}
+
+// Names returns the names of the registered models, in the order they were added.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.names...)
+}
+
+// Get returns the information about the named model.
+func (r *Registry) Get(name string) (ModelInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.models[name]
+	if !ok {
+		return ModelInfo{}, fmt.Errorf("%w: %q", ErrModelNotFound, name)
+	}
+	return m.info(name), nil
+}
+
+// Models returns the information about the registered models, in the order
+// they were added.
+func (r *Registry) Models() []ModelInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]ModelInfo, len(r.names))
+	for i, name := range r.names {
+		m := r.models[name]
+		infos[i] = m.info(name)
+	}
+	return infos
+}
+
+// setHealth reports the serving status of each model, using the model name
+// as service name, on the given health server.
+func (r *Registry) setHealth(h *health.Server) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.health = h
+	for _, name := range r.names {
+		r.setServingStatus(name, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+}
+
+// setServingStatus sets the serving status of the named model, if a health
+// server has been set. It must be called with the lock held.
+func (r *Registry) setServingStatus(name string, st grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	if r.health != nil {
+		r.health.SetServingStatus(name, st)
+	}
+}
+
+// RegisterServer registers the services of all tasks.
+func (r *Registry) RegisterServer(sr grpc.ServiceRegistrar) error {
+	for _, h := range r.handlers() {
+		if err := h.RegisterServer(sr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterHandlerServer registers the HTTP handlers of all tasks.
+func (r *Registry) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	for _, h := range r.handlers() {
+		if err := h.RegisterHandlerServer(ctx, mux); err != nil {
+			return err
+		}
+	}
+	// registered last, so that they take precedence over the task handlers
+	if err := mux.HandlePath(http.MethodPost, "/v1/classify", r.handleClassify(mux, "")); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodPost, "/v1/classify:batch", r.handleClassify(mux, ":batch"))
+}
+
+// handleClassify serves "/v1/classify" and its verbs (e.g. ":batch"), which
+// are shared by text, token and zero-shot classification, forwarding the
+// request to the task-specific path (e.g. "/v1/token-classification/classify")
+// of the requested model.
+func (r *Registry) handleClassify(mux *runtime.ServeMux, verb string) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, _ map[string]string) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			_, outbound := runtime.MarshalerForRequest(mux, req)
+			runtime.HTTPError(req.Context(), mux, outbound, w, req, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		// a malformed body is reported by the task handler
+		var fields struct {
+			Model string `json:"model"`
+		}
+		_ = json.Unmarshal(body, &fields)
+		name := fields.Model
+		if name == "" {
+			name = req.Header.Get("Grpc-Metadata-" + ModelMetadataKey)
+		}
+
+		req.URL.Path = fmt.Sprintf("/v1/%s/classify%s", r.classificationTask(name), verb)
+		mux.ServeHTTP(w, req)
+	}
+}
+
+// classificationTask returns the classification task of the named model, or
+// of the first classification model if name is empty. It defaults to text
+// classification, leaving to the task handler to report unknown models.
+func (r *Registry) classificationTask(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	isClassification := func(task string) bool {
+		return task == "text-classification" || task == "token-classification" || task == "zero-shot-classification"
+	}
+	if name != "" {
+		if m, ok := r.models[name]; ok && isClassification(TaskOf(m.model)) {
+			return TaskOf(m.model)
+		}
+		return "text-classification"
+	}
+	for _, n := range r.names {
+		if task := TaskOf(r.models[n].model); isClassification(task) {
+			return task
+		}
+	}
+	return "text-classification"
+}
+
+// handlers returns a RequestHandler for each task, routing the requests to
+// the models of the registry.
+func (r *Registry) handlers() []RequestHandler {
+	return []RequestHandler{
+		&serverForTextGeneration{generators: registryResolver[textgeneration.Interface](r)},
+		&serverForZeroShotClassification{classifiers: registryResolver[zeroshotclassifier.Interface](r)},
+		&serverForQuestionAnswering{engines: registryResolver[questionanswering.Interface](r)},
+		&serverForTextClassification{classifiers: registryResolver[textclassification.Interface](r)},
+		&serverForTextEncoding{encoders: registryResolver[textencoding.Interface](r)},
+		&serverForTokenClassification{classifiers: registryResolver[tokenclassification.Interface](r)},
+		&serverForLanguageModeling{predictors: registryResolver[languagemodeling.Interface](r)},
+	}
+}
+
+// registryResolver returns a modelResolver looking up the models of type T
+// in the registry. The model name is taken from the request message or,
+// if empty, from the ModelMetadataKey metadata.
+func registryResolver[T any](r *Registry) modelResolver[T] {
+	return func(ctx context.Context, req modelRequest) (T, func(), error) {
+		var zero T
+		done, err := admit(ctx, req)
+		if err != nil {
+			return zero, nil, err
+		}
+		m, release, err := lookupModel[T](ctx, r, req.GetModel())
+		if err != nil {
+			done()
+			return zero, nil, err
+		}
+		return m, func() {
+			release()
+			done()
+		}, nil
+	}
+}
+
+// lookupModel returns the named model of type T, or the first one if name
+// and the model metadata are empty, acquiring it until released.
+func lookupModel[T any](ctx context.Context, r *Registry, name string) (T, func(), error) {
+	var zero T
+	if name == "" {
+		name = modelFromMetadata(ctx)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name == "" {
+		for _, n := range r.names {
+			if m, ok := r.models[n].model.(T); ok {
+				observeModel(ctx, n, r.models[n].version)
+				return m, r.models[n].acquire(), nil
+			}
+		}
+		return zero, nil, status.Error(codes.Unimplemented, "no model loaded for the requested task")
+	}
+
+	rm, ok := r.models[name]
+	if !ok {
+		return zero, nil, status.Errorf(codes.NotFound, "model %q not found", name)
+	}
+	m, ok := rm.model.(T)
+	if !ok {
+		return zero, nil, status.Errorf(codes.InvalidArgument, "model %q does not support the requested task (%s)", name, TaskOf(rm.model))
+	}
+	observeModel(ctx, name, rm.version)
+	return m, rm.acquire(), nil
+}
+
+// observeModel records the name of the model serving the request in its
+// span, and its name and version in its access log entry.
+func observeModel(ctx context.Context, name, version string) {
+	trace.SpanFromContext(ctx).SetAttributes(instrument.ModelKey.String(name))
+	logModel(ctx, name, version)
+}
+
+// modelFromMetadata returns the model name set in the incoming metadata, if any.
+func modelFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(ModelMetadataKey); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// TaskOf returns the name of the task fulfilled by the model, or an empty
+// string if the model does not implement any known task interface.
+func TaskOf(model any) string {
+	switch model.(type) {
+	case textgeneration.Interface:
+		return "text-generation"
+	case zeroshotclassifier.Interface:
+		return "zero-shot-classification"
+	case questionanswering.Interface:
+		return "question-answering"
+	case textclassification.Interface:
+		return "text-classification"
+	case textencoding.Interface:
+		return "text-encoding"
+	case tokenclassification.Interface:
+		return "token-classification"
+	case languagemodeling.Interface:
+		return "language-modeling"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	textclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textclassification/v1"
+	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeClassifier struct {
+	label string
+}
+
+func (c fakeClassifier) Classify(context.Context, string) (textclassification.Response, error) {
+	return textclassification.Response{Labels: []string{c.label}, Scores: []float64{1}}, nil
+}
+
+func (c fakeClassifier) ClassifyBatch(ctx context.Context, texts []string) ([]textclassification.Response, error) {
+	responses := make([]textclassification.Response, len(texts))
+	for i, text := range texts {
+		responses[i], _ = c.Classify(ctx, text)
+	}
+	return responses, nil
+}
+
+type fakeEncoder struct{}
+
+func (fakeEncoder) Encode(context.Context, string, int) (textencoding.Response, error) {
+	return textencoding.Response{}, nil
+}
+
+func (fakeEncoder) EncodeBatch(_ context.Context, texts []string, _ int) ([]textencoding.Response, error) {
+	return make([]textencoding.Response, len(texts)), nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("sentiment", fakeClassifier{label: "positive"}))
+	require.NoError(t, r.Add("embeddings", fakeEncoder{}))
+	require.NoError(t, r.Add("topics", fakeClassifier{label: "sports"}))
+
+	assert.Error(t, r.Add("topics", fakeClassifier{}), "duplicate name")
+	assert.Error(t, r.Add("", fakeClassifier{}), "empty name")
+	assert.Error(t, r.Add("unknown", struct{}{}), "unsupported task")
+	assert.Equal(t, []string{"sentiment", "embeddings", "topics"}, r.Names())
+
+	classifiers := registryResolver[textclassification.Interface](r)
+	label := func(ctx context.Context, name string) string {
+		c, release, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: name})
+		require.NoError(t, err)
+		defer release()
+		resp, err := c.Classify(ctx, "")
+		require.NoError(t, err)
+		return resp.Labels[0]
+	}
+
+	ctx := context.Background()
+	t.Run("default model", func(t *testing.T) {
+		assert.Equal(t, "positive", label(ctx, ""))
+	})
+	t.Run("by name", func(t *testing.T) {
+		assert.Equal(t, "sports", label(ctx, "topics"))
+	})
+	t.Run("by metadata", func(t *testing.T) {
+		mdCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(ModelMetadataKey, "topics"))
+		assert.Equal(t, "sports", label(mdCtx, ""))
+		assert.Equal(t, "positive", label(mdCtx, "sentiment"), "the request field takes precedence")
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "missing"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+	t.Run("wrong task", func(t *testing.T) {
+		_, _, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "embeddings"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRegistry_Swap(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("topics", fakeClassifier{label: "old"}))
+	classifiers := registryResolver[textclassification.Interface](r)
+
+	ctx := context.Background()
+	old, release, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "topics"})
+	require.NoError(t, err)
+
+	swapped := make(chan error)
+	go func() {
+		swapped <- r.Swap(ctx, "topics", fakeClassifier{label: "new"})
+	}()
+
+	// new requests are routed to the new model while the old one is draining
+	require.Eventually(t, func() bool {
+		c, release, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "topics"})
+		if err != nil {
+			return false
+		}
+		defer release()
+		return c.(fakeClassifier).label == "new"
+	}, time.Second, time.Millisecond)
+
+	select {
+	case <-swapped:
+		t.Fatal("swap completed before the in-flight request was served")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.Equal(t, "old", old.(fakeClassifier).label)
+	release()
+	assert.NoError(t, <-swapped)
+
+	assert.Error(t, r.Swap(ctx, "topics", fakeEncoder{}), "different task")
+	assert.ErrorIs(t, r.Swap(ctx, "missing", fakeClassifier{}), ErrModelNotFound)
+
+	t.Run("context done while draining", func(t *testing.T) {
+		_, release, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "topics"})
+		require.NoError(t, err)
+		defer release()
+
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, r.Swap(timeout, "topics", fakeClassifier{label: "newer"}), ErrModelDraining)
+		c, release2, err := classifiers(ctx, &textclassificationv1.ClassifyRequest{Model: "topics"})
+		require.NoError(t, err)
+		defer release2()
+		assert.Equal(t, "newer", c.(fakeClassifier).label, "the swap is applied")
+	})
+}
+
+func TestRegistry_Remove(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("topics", fakeClassifier{label: "sports"}))
+	classifiers := registryResolver[textclassification.Interface](r)
+
+	_, release, err := classifiers(context.Background(), &textclassificationv1.ClassifyRequest{Model: "topics"})
+	require.NoError(t, err)
+	assert.Equal(t, []ModelInfo{{Name: "topics", Task: "text-classification", InFlight: 1}}, r.Models())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = r.Remove(ctx, "topics")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the request is still in flight")
+	assert.ErrorIs(t, err, ErrModelDraining)
+	assert.Empty(t, r.Names())
+
+	_, _, err = classifiers(context.Background(), &textclassificationv1.ClassifyRequest{Model: "topics"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	release()
+
+	assert.ErrorIs(t, r.Remove(context.Background(), "topics"), ErrModelNotFound)
+}
@@ -0,0 +1,334 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// PriorityMetadataKey is the gRPC metadata key (and HTTP header) setting
+	// the priority class of a request: "interactive", "normal" (the
+	// default) or "batch".
+	PriorityMetadataKey = "cybertron-priority"
+	// DefaultPriorityAgingTime is the default time after which a waiting
+	// request is served before the ones of higher priority.
+	DefaultPriorityAgingTime = 10 * time.Second
+)
+
+// Priority is the class of a request, deciding the order in which the
+// waiting requests are served.
+type Priority int
+
+const (
+	// PriorityBatch is the class of bulk requests, such as backfills, served
+	// when no request of higher priority is waiting.
+	PriorityBatch Priority = iota
+	// PriorityNormal is the class of the requests without a priority.
+	PriorityNormal
+	// PriorityInteractive is the class of latency-sensitive requests, served
+	// before any other.
+	PriorityInteractive
+
+	numPriorities = int(PriorityInteractive) + 1
+)
+
+// ParsePriority parses the name of a priority class.
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "batch":
+		return PriorityBatch, nil
+	case "normal", "":
+		return PriorityNormal, nil
+	case "interactive":
+		return PriorityInteractive, nil
+	default:
+		return 0, fmt.Errorf("invalid priority %q: expected \"interactive\", \"normal\" or \"batch\"", s)
+	}
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBatch:
+		return "batch"
+	case PriorityNormal:
+		return "normal"
+	case PriorityInteractive:
+		return "interactive"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+type priorityKey struct{}
+
+// withPriorityHeader returns a copy of the context carrying the priority set
+// by the header of an HTTP request, which the gateway would not forward as
+// metadata.
+func withPriorityHeader(ctx context.Context, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, priorityKey{}, value)
+}
+
+// priorityFromContext returns the priority of a request, set by its HTTP
+// header or its incoming metadata, failing with InvalidArgument if unknown.
+func priorityFromContext(ctx context.Context) (Priority, error) {
+	value, ok := ctx.Value(priorityKey{}).(string)
+	if !ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		value = firstValue(md, PriorityMetadataKey)
+	}
+	p, err := ParsePriority(value)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return p, nil
+}
+
+// scheduler grants the slots of a bounded pool of inference workers to the
+// requests waiting for them.
+//
+// The waiting requests are served by priority class, and within each class
+// by weighted fair queuing between tenants (start-time fair queuing, each
+// request costing the inverse of the weight of its tenant), so that a tenant
+// sending many requests does not delay the others for long. Strict priority
+// would starve the lower classes under sustained load: a request waiting for
+// longer than agingTime is served first, whatever its class.
+type scheduler struct {
+	workers        int
+	maxQueueLength int
+	maxQueueTime   time.Duration
+	agingTime      time.Duration
+	now            func() time.Time
+
+	mu      sync.Mutex
+	running int
+	classes [numPriorities]schedulerClass
+	// arrivals are the waiters in order of arrival.
+	arrivals list.List
+	seq      uint64
+}
+
+// schedulerClass is the queue of the requests of a priority class.
+type schedulerClass struct {
+	waiting waiterHeap
+	// vtime is the virtual time of the class: the latest start tag of the
+	// requests granted a slot.
+	vtime float64
+	// finish are the finish tags of the last requests of the tenants.
+	finish map[string]float64
+}
+
+// waiter is a request waiting for a slot.
+type waiter struct {
+	priority Priority
+	tenant   string
+	// start and finish are the virtual times tagging the request.
+	start, finish float64
+	seq           uint64
+	enqueued      time.Time
+	// ready is closed once the request is granted a slot.
+	ready chan struct{}
+	// index is the position in the heap of its class, -1 once removed.
+	index   int
+	arrival *list.Element
+}
+
+func newScheduler(conf *Config) *scheduler {
+	agingTime := conf.PriorityAgingTime
+	if agingTime == 0 {
+		agingTime = DefaultPriorityAgingTime
+	}
+	s := &scheduler{
+		workers:        conf.MaxConcurrentInferences,
+		maxQueueLength: conf.MaxQueueLength,
+		maxQueueTime:   conf.MaxQueueTime,
+		agingTime:      agingTime,
+		now:            time.Now,
+	}
+	for i := range s.classes {
+		s.classes[i].finish = make(map[string]float64)
+	}
+	return s
+}
+
+// acquire waits for a slot for a request of the given priority, sent by the
+// tenant with the given weight. It returns the function to call once the
+// request has been served.
+//
+// It fails with ResourceExhausted if the queue is full or if the request
+// waited in queue longer than maxQueueTime.
+func (s *scheduler) acquire(ctx context.Context, priority Priority, tenant string, weight float64) (func(), error) {
+	s.mu.Lock()
+	if s.running < s.workers && s.arrivals.Len() == 0 {
+		s.running++
+		s.mu.Unlock()
+		return s.release, nil
+	}
+	if s.maxQueueLength > 0 && s.arrivals.Len() >= s.maxQueueLength {
+		s.mu.Unlock()
+		return nil, resourceExhausted(ReasonQueueFull, "too many requests waiting to be served")
+	}
+	w := s.enqueue(priority, tenant, weight)
+	s.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if s.maxQueueTime > 0 {
+		timer := time.NewTimer(s.maxQueueTime)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-w.ready:
+		return s.release, nil
+	case <-timeout:
+		if !s.cancel(w) {
+			return s.release, nil // granted in the meantime
+		}
+		return nil, resourceExhausted(ReasonQueueTimeout, fmt.Sprintf("request not served within %s", s.maxQueueTime))
+	case <-ctx.Done():
+		if !s.cancel(w) {
+			s.release()
+		}
+		return nil, statusError(ctx.Err())
+	}
+}
+
+// enqueue adds a waiter to the queue of its class. It must be called with
+// the mutex held.
+func (s *scheduler) enqueue(priority Priority, tenant string, weight float64) *waiter {
+	if weight <= 0 {
+		weight = 1
+	}
+	c := &s.classes[priority]
+	s.seq++
+	w := &waiter{
+		priority: priority,
+		tenant:   tenant,
+		start:    max(c.vtime, c.finish[tenant]),
+		seq:      s.seq,
+		enqueued: s.now(),
+		ready:    make(chan struct{}),
+	}
+	w.finish = w.start + 1/weight
+	c.finish[tenant] = w.finish
+	heap.Push(&c.waiting, w)
+	w.arrival = s.arrivals.PushBack(w)
+	return w
+}
+
+// cancel removes the waiter from the queue, reporting whether it was still
+// waiting, i.e. it has not been granted a slot.
+func (s *scheduler) cancel(w *waiter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w.index < 0 {
+		return false
+	}
+	s.remove(w)
+	return true
+}
+
+// remove removes the waiter from the queue. It must be called with the
+// mutex held.
+func (s *scheduler) remove(w *waiter) {
+	heap.Remove(&s.classes[w.priority].waiting, w.index)
+	s.arrivals.Remove(w.arrival)
+}
+
+// release frees a slot, granting it to the next waiter, if any.
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	for s.running < s.workers && s.arrivals.Len() > 0 {
+		w := s.next()
+		s.remove(w)
+		c := &s.classes[w.priority]
+		c.vtime = max(c.vtime, w.start)
+		if c.finish[w.tenant] <= c.vtime {
+			delete(c.finish, w.tenant) // idle tenant, same as unknown
+		}
+		s.running++
+		close(w.ready)
+	}
+}
+
+// next returns the waiter to serve next: the oldest one if it has waited
+// for longer than the aging time, otherwise the first one of the highest
+// priority class. It must be called with the mutex held.
+func (s *scheduler) next() *waiter {
+	oldest := s.arrivals.Front().Value.(*waiter)
+	if s.agingTime > 0 && s.now().Sub(oldest.enqueued) >= s.agingTime {
+		return oldest
+	}
+	for p := numPriorities - 1; p >= 0; p-- {
+		if c := &s.classes[p]; len(c.waiting) > 0 {
+			return c.waiting[0]
+		}
+	}
+	return oldest
+}
+
+// inProgress returns the number of slots in use.
+func (s *scheduler) inProgress() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// queued returns the number of waiting requests.
+func (s *scheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.arrivals.Len()
+}
+
+// waiterHeap is a heap.Interface of waiters, ordered by finish tag and
+// arrival.
+type waiterHeap []*waiter
+
+func (h waiterHeap) Len() int { return len(h) }
+
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].finish != h[j].finish {
+		return h[i].finish < h[j].finish
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiterHeap) Pop() any {
+	old := *h
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.index = -1
+	*h = old[:len(old)-1]
+	return w
+}
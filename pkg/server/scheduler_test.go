@@ -0,0 +1,162 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// servedOrder records the order in which the requests queued on a scheduler
+// are served.
+type servedOrder struct {
+	t  *testing.T
+	s  *scheduler
+	wg sync.WaitGroup
+	mu sync.Mutex
+	// names are the names of the requests served.
+	names []string
+}
+
+// queue queues a request, released as soon as it is served.
+func (o *servedOrder) queue(name string, priority Priority, tenant string, weight float64) {
+	queued := o.s.queued()
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		release, err := o.s.acquire(context.Background(), priority, tenant, weight)
+		if !assert.NoError(o.t, err) {
+			return
+		}
+		o.mu.Lock()
+		o.names = append(o.names, name)
+		o.mu.Unlock()
+		release()
+	}()
+	require.Eventually(o.t, func() bool { return o.s.queued() == queued+1 }, time.Second, time.Millisecond)
+}
+
+// serve releases the slot held by the test, returning the order in which
+// the queued requests are served.
+func (o *servedOrder) serve(release func()) []string {
+	release()
+	o.wg.Wait()
+	return o.names
+}
+
+// newBusyScheduler returns a scheduler with a single worker, whose slot is
+// held until the returned function is called.
+func newBusyScheduler(t *testing.T, conf *Config) (*servedOrder, func()) {
+	conf.MaxConcurrentInferences = 1
+	s := newScheduler(conf)
+	release, err := s.acquire(context.Background(), PriorityNormal, "", 1)
+	require.NoError(t, err)
+	return &servedOrder{t: t, s: s}, release
+}
+
+func TestScheduler_Priority(t *testing.T) {
+	o, release := newBusyScheduler(t, &Config{PriorityAgingTime: -1})
+	o.queue("batch", PriorityBatch, "", 1)
+	o.queue("normal", PriorityNormal, "", 1)
+	o.queue("interactive", PriorityInteractive, "", 1)
+	o.queue("normal 2", PriorityNormal, "", 1)
+
+	assert.Equal(t, []string{"interactive", "normal", "normal 2", "batch"}, o.serve(release))
+}
+
+func TestScheduler_FairQueuing(t *testing.T) {
+	t.Run("same weight", func(t *testing.T) {
+		o, release := newBusyScheduler(t, &Config{})
+		o.queue("bulk 1", PriorityNormal, "bulk", 1)
+		o.queue("bulk 2", PriorityNormal, "bulk", 1)
+		o.queue("bulk 3", PriorityNormal, "bulk", 1)
+		o.queue("user 1", PriorityNormal, "user", 1)
+
+		assert.Equal(t, []string{"bulk 1", "user 1", "bulk 2", "bulk 3"}, o.serve(release),
+			"the requests of a tenant do not wait for all the ones queued by another")
+	})
+	t.Run("weighted", func(t *testing.T) {
+		o, release := newBusyScheduler(t, &Config{})
+		for _, name := range []string{"1", "2", "3"} {
+			o.queue("light "+name, PriorityNormal, "light", 1)
+			o.queue("heavy "+name, PriorityNormal, "heavy", 2)
+		}
+
+		assert.Equal(t, []string{"heavy 1", "light 1", "heavy 2", "heavy 3", "light 2", "light 3"}, o.serve(release))
+	})
+}
+
+func TestScheduler_Aging(t *testing.T) {
+	o, release := newBusyScheduler(t, &Config{PriorityAgingTime: time.Minute})
+	now := time.Now()
+	o.s.now = func() time.Time { return now }
+	o.queue("batch", PriorityBatch, "", 1)
+	now = now.Add(time.Minute)
+	o.queue("interactive", PriorityInteractive, "", 1)
+
+	assert.Equal(t, []string{"batch", "interactive"}, o.serve(release),
+		"the request waiting longer than the aging time is not starved")
+}
+
+func TestScheduler_Canceled(t *testing.T) {
+	o, release := newBusyScheduler(t, &Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := o.s.acquire(ctx, PriorityInteractive, "", 1)
+		canceled <- err
+	}()
+	require.Eventually(t, func() bool { return o.s.queued() == 1 }, time.Second, time.Millisecond)
+	o.queue("normal", PriorityNormal, "", 1)
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-canceled))
+	assert.Equal(t, []string{"normal"}, o.serve(release))
+	assert.Equal(t, 0, o.s.inProgress())
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	p, err := priorityFromContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, PriorityNormal, p)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(PriorityMetadataKey, "batch"))
+	p, err = priorityFromContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, PriorityBatch, p)
+
+	p, err = priorityFromContext(withPriorityHeader(ctx, "Interactive"))
+	require.NoError(t, err)
+	assert.Equal(t, PriorityInteractive, p, "the HTTP header takes precedence")
+
+	_, err = priorityFromContext(withPriorityHeader(ctx, "urgent"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_Priority(t *testing.T) {
+	s := startServer(t, &Config{MaxConcurrentInferences: 1}, NewServerForTextEncoding(instrumentedEncoder{}))
+
+	post := func(priority string) int {
+		req, err := http.NewRequest(http.MethodPost, "http://"+s.ClientAddr()+"/v1/encode", strings.NewReader(`{"input": "a"}`))
+		require.NoError(t, err)
+		req.Header.Set(PriorityMetadataKey, priority)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, post("interactive"))
+	assert.Equal(t, http.StatusBadRequest, post("urgent"))
+}
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/cors"
+	"github.com/rs/zerolog/log"
 	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
 	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
 	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
@@ -22,13 +24,13 @@ import (
 	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
 	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
 	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
-	"github.com/rs/cors"
-	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
@@ -40,9 +42,10 @@ const (
 
 // Server is a server that provides gRPC and HTTP/2 APIs.
 type Server struct {
-	conf    *Config
-	handler RequestHandler
-	health  *health.Server
+	conf      *Config
+	handler   RequestHandler
+	health    *health.Server
+	readiness *readiness
 }
 
 // Config is the configuration for the server.
@@ -51,8 +54,112 @@ type Config struct {
 	Address        string
 	AllowedOrigins []string
 	TLSEnabled     bool
-	TLSCert        string
-	TLSKey         string
+	// TLSCert and TLSKey are the files of the certificate of the server.
+	// They are reloaded when they change, without restarting the server.
+	TLSCert string
+	TLSKey  string
+	// TLSClientCA is the file of the bundle of PEM certificates verifying the
+	// client certificates. If set, the clients must present a certificate
+	// (mutual TLS).
+	TLSClientCA string
+	// TLSClientSubjects are the subjects of the client certificates allowed
+	// to call the server, matching the common name, the distinguished name or
+	// an alternative name of the certificates. If empty, any client with a
+	// valid certificate is allowed.
+	TLSClientSubjects []string
+	// MaxBatchSize is the maximum number of concurrent requests run together
+	// in a single forward pass. Dynamic batching is disabled if it is <= 1.
+	MaxBatchSize int
+	// MaxBatchWait is the maximum time a request waits for other requests
+	// to join its batch (default DefaultMaxBatchWait).
+	MaxBatchWait time.Duration
+	// CacheSize is the maximum number of responses cached for each model by
+	// WithResponseCache. Caching is disabled if it is <= 0.
+	CacheSize int
+	// CacheTTL is the time after which the cached responses expire. They
+	// never expire if it is <= 0.
+	CacheTTL time.Duration
+	// MetricsEnabled enables the collection of the metrics, served in the
+	// Prometheus text format on the /metrics HTTP route. They are disabled
+	// by default, and enabled by default by the server command.
+	MetricsEnabled bool
+	// TracerProvider enables the tracing of the requests, and of the stages
+	// of their processing, with spans created by this provider (see
+	// NewTracerProvider). Tracing is disabled if nil.
+	TracerProvider trace.TracerProvider
+	// MaxConcurrentInferences is the maximum number of requests served at
+	// the same time; the others wait in queue. No limit if <= 0.
+	MaxConcurrentInferences int
+	// MaxQueueLength is the maximum number of requests waiting for their
+	// turn; the exceeding ones fail with ResourceExhausted. No limit if <= 0.
+	MaxQueueLength int
+	// MaxQueueTime is the maximum time a request waits for its turn before
+	// failing with ResourceExhausted. No limit if <= 0.
+	MaxQueueTime time.Duration
+	// PriorityAgingTime is the time after which a request waiting for its
+	// turn is served before the ones of higher priority, so that the lower
+	// priorities are not starved (default DefaultPriorityAgingTime). Strict
+	// priority if < 0.
+	PriorityAgingTime time.Duration
+	// RequestTimeout is the maximum time to serve a request, queue included,
+	// after which it fails with DeadlineExceeded. No limit if <= 0.
+	RequestTimeout time.Duration
+	// MaxInputChars is the maximum number of characters of each text of a
+	// request; longer texts fail with InvalidArgument. No limit if <= 0.
+	MaxInputChars int
+	// MaxInputTokens is the maximum number of tokens of the input sequences,
+	// lowering the limit of the models. No limit if <= 0.
+	MaxInputTokens int
+	// MaxCandidateLabels is the maximum number of candidate labels of a
+	// zero-shot classification request. No limit if <= 0.
+	MaxCandidateLabels int
+	// MaxBatchInputs is the maximum number of inputs of a batch request;
+	// larger requests fail with InvalidArgument. No limit if <= 0.
+	MaxBatchInputs int
+	// APIKeys are the keys, or static bearer tokens, accepted by the server,
+	// with the limits of their clients. If empty, authentication is disabled.
+	APIKeys []APIKey
+	// QuotaPeriod is the period of the token quotas of the API keys
+	// (default DefaultQuotaPeriod).
+	QuotaPeriod time.Duration
+	// StartNotReady makes the server start listening before being ready to
+	// serve requests, e.g. while its models are loaded in the background:
+	// it reports NOT_SERVING on the health service and on /readyz, and fails
+	// the requests with Unavailable, until SetReady is called.
+	StartNotReady bool
+	// ReflectionEnabled enables the gRPC server reflection service, letting
+	// tools such as grpcurl discover the services of the server.
+	ReflectionEnabled bool
+	// AccessLogEnabled enables the access logs: an entry for each request,
+	// with its ID (see RequestIDMetadataKey), method, model, input length in
+	// characters and tokens, latency, status code and peer. They are
+	// disabled by default, and enabled by default by the server command.
+	AccessLogEnabled bool
+	// PayloadLogSampleRate is the fraction of the requests, between 0 and 1,
+	// whose request and response payloads are added to their access log
+	// entry, once redacted by PayloadRedactor. The payloads are not logged
+	// if <= 0.
+	PayloadLogSampleRate float64
+	// PayloadRedactor redacts the payloads before they are logged (default
+	// RedactStrings).
+	PayloadRedactor Redactor
+	// CaptureDir is the directory where a sample of the successful unary
+	// requests is captured, along with their responses, model version and
+	// latency, to JSON Lines files usable as input of offline batch runs.
+	// The traffic is not captured if empty.
+	CaptureDir string
+	// CaptureSampleRate is the fraction of the requests, between 0 and 1,
+	// captured to CaptureDir (default 1).
+	CaptureSampleRate float64
+	// CaptureMaxFileSize is the size in bytes after which a capture file is
+	// rotated (default DefaultCaptureMaxFileSize).
+	CaptureMaxFileSize int64
+	// CaptureMaxFiles is the number of capture files kept, the oldest ones
+	// being deleted on rotation (default DefaultCaptureMaxFiles).
+	CaptureMaxFiles int
+	// CaptureRedactor redacts the requests and responses before they are
+	// captured. They are captured as they are if nil.
+	CaptureRedactor Redactor
 }
 
 // RequestHandler is implemented by any task-specific service that can be
@@ -62,6 +169,63 @@ type RequestHandler interface {
 	RegisterHandlerServer(context.Context, *runtime.ServeMux) error
 }
 
+// JoinRequestHandlers returns a RequestHandler registering all the given handlers.
+func JoinRequestHandlers(handlers ...RequestHandler) RequestHandler {
+	return requestHandlers(handlers)
+}
+
+type requestHandlers []RequestHandler
+
+func (hs requestHandlers) RegisterServer(r grpc.ServiceRegistrar) error {
+	for _, h := range hs {
+		if err := h.RegisterServer(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hs requestHandlers) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	for _, h := range hs {
+		if err := h.RegisterHandlerServer(ctx, mux); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hs requestHandlers) Models() []ModelInfo {
+	var models []ModelInfo
+	for _, handler := range hs {
+		if ml, ok := handler.(modelLister); ok {
+			models = append(models, ml.Models()...)
+		}
+	}
+	return models
+}
+
+func (hs requestHandlers) setHealth(h *health.Server) {
+	for _, handler := range hs {
+		if hr, ok := handler.(healthReporter); ok {
+			hr.setHealth(h)
+		}
+	}
+}
+
+func (hs requestHandlers) setShadowMetrics(m *metrics) {
+	for _, handler := range hs {
+		if sr, ok := handler.(shadowReporter); ok {
+			sr.setShadowMetrics(m)
+		}
+	}
+}
+
+// healthReporter is implemented by the request handlers reporting their own
+// serving status on the health server.
+type healthReporter interface {
+	setHealth(*health.Server)
+}
+
 // ResolveRequestHandler instantiates a new task-server based on the model.
 func ResolveRequestHandler(model any) (RequestHandler, error) {
 	switch m := model.(type) {
@@ -87,11 +251,17 @@ func ResolveRequestHandler(model any) (RequestHandler, error) {
 // New creates a new server.
 func New(conf *Config, handler RequestHandler) *Server {
 	setBaselineConfig(conf)
-	return &Server{
-		conf:    conf,
-		handler: handler,
-		health:  health.NewServer(),
+	h := health.NewServer()
+	s := &Server{
+		conf:      conf,
+		handler:   handler,
+		health:    h,
+		readiness: newReadiness(!conf.StartNotReady, h),
+	}
+	if hr, ok := handler.(healthReporter); ok {
+		hr.setHealth(s.health)
 	}
+	return s
 }
 
 func setBaselineConfig(c *Config) {
@@ -103,22 +273,124 @@ func setBaselineConfig(c *Config) {
 	}
 }
 
+// SetReady marks the server as ready to serve requests, if it was created
+// with StartNotReady, reporting SERVING on the health service.
+func (s *Server) SetReady() {
+	s.readiness.setReady()
+}
+
 // Start up the server and block until the context is done.
 func (s *Server) Start(ctx context.Context) error {
 	conf := s.conf
 
-	grpcServer := grpc.NewServer()
+	var (
+		m                  *metrics
+		sa                 subjectAuthorizer
+		au                 *auth
+		a                  = newAdmission(conf)
+		serverOpts         []grpc.ServerOption
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+		muxOpts            = []runtime.ServeMuxOption{runtime.WithErrorHandler(httpErrorHandler)}
+	)
+	if conf.TracerProvider != nil {
+		serverOpts = append(serverOpts, tracingServerOption(conf.TracerProvider))
+		muxOpts = append(muxOpts, tracingServeMuxOption())
+	}
+	if conf.MetricsEnabled {
+		m = newMetrics()
+		if ml, ok := s.handler.(modelLister); ok {
+			m.registerModels(ml)
+		}
+		m.registerAdmission(a)
+		if sr, ok := s.handler.(shadowReporter); ok {
+			sr.setShadowMetrics(m)
+		}
+		unaryInterceptors = append(unaryInterceptors, m.unaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, m.streamServerInterceptor())
+		muxOpts = append(muxOpts, m.serveMuxOption())
+	}
+	al, err := newAccessLogger(conf)
+	if err != nil {
+		return fmt.Errorf("failed to set up traffic capture: %w", err)
+	}
+	if al != nil {
+		defer func() {
+			if err := al.close(); err != nil {
+				log.Err(err).Msg("failed to close traffic capture")
+			}
+		}()
+		unaryInterceptors = append(unaryInterceptors, al.unaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, al.streamServerInterceptor())
+		muxOpts = append(muxOpts, al.serveMuxOptions()...)
+	}
+	if len(conf.TLSClientSubjects) > 0 {
+		if !conf.TLSEnabled || conf.TLSClientCA == "" {
+			return errors.New("client certificate subjects require TLS and a client CA")
+		}
+		sa = newSubjectAuthorizer(conf.TLSClientSubjects)
+		unaryInterceptors = append(unaryInterceptors, subjectsUnaryServerInterceptor(sa))
+		streamInterceptors = append(streamInterceptors, subjectsStreamServerInterceptor(sa))
+	}
+	if len(conf.APIKeys) > 0 {
+		var err error
+		if au, err = newAuth(conf); err != nil {
+			return fmt.Errorf("failed to set up authentication: %w", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, authUnaryServerInterceptor(au))
+		streamInterceptors = append(streamInterceptors, authStreamServerInterceptor(au))
+	}
+	unaryInterceptors = append(unaryInterceptors,
+		readinessUnaryServerInterceptor(s.readiness),
+		admissionUnaryServerInterceptor(a, conf),
+		errorsUnaryServerInterceptor(),
+	)
+	streamInterceptors = append(streamInterceptors,
+		readinessStreamServerInterceptor(s.readiness),
+		admissionStreamServerInterceptor(a, conf),
+		errorsStreamServerInterceptor(),
+	)
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+
+	grpcServer := grpc.NewServer(serverOpts...)
 
 	grpc_health_v1.RegisterHealthServer(grpcServer, s.health)
 
 	if err := s.handler.RegisterServer(grpcServer); err != nil {
 		return fmt.Errorf("failed to register gRPC server: %w", err)
 	}
+	if conf.ReflectionEnabled {
+		reflection.Register(grpcServer)
+	}
+	openAPIDoc, err := newOpenAPIDocument(grpcServer.GetServiceInfo())
+	if err != nil {
+		return fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
 
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(muxOpts...)
 	if err := s.handler.RegisterHandlerServer(ctx, mux); err != nil {
 		return fmt.Errorf("failed to register gRPC handler server: %w", err)
 	}
+	if m != nil {
+		if err := mux.HandlePath(http.MethodGet, metricsPath, m.handler()); err != nil {
+			return fmt.Errorf("failed to register metrics handler: %w", err)
+		}
+	}
+	if err := mux.HandlePath(http.MethodGet, livenessPath, livenessHandler); err != nil {
+		return fmt.Errorf("failed to register liveness handler: %w", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, readinessPath, s.readiness.readinessHandler); err != nil {
+		return fmt.Errorf("failed to register readiness handler: %w", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, openAPIPath, openAPIHandler(openAPIDoc)); err != nil {
+		return fmt.Errorf("failed to register OpenAPI handler: %w", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, docsPath, docsHandler); err != nil {
+		return fmt.Errorf("failed to register docs handler: %w", err)
+	}
 
 	lis, err := net.Listen(conf.Network, conf.Address)
 	if err != nil {
@@ -129,7 +401,24 @@ func (s *Server) Start(ctx context.Context) error {
 		conf.Address = lis.Addr().String()
 	}
 
-	handler := cors.New(s.corsOptions()).Handler(mux)
+	handler := admissionMiddleware(a, conf, mux)
+	handler = readinessMiddleware(s.readiness, mux, handler)
+	if au != nil {
+		handler = authMiddleware(au, mux, handler)
+	}
+	if sa != nil {
+		handler = subjectsMiddleware(sa, mux, handler)
+	}
+	handler = cors.New(s.corsOptions()).Handler(handler)
+	if al != nil {
+		handler = al.httpMiddleware(handler)
+	}
+	if m != nil {
+		handler = m.httpMiddleware(handler)
+	}
+	if conf.TracerProvider != nil {
+		handler = tracingMiddleware(conf.TracerProvider, handler)
+	}
 	handler = s.handlerFunc(grpcServer, handler)
 
 	err = s.serve(ctx, lis, handler)
@@ -175,20 +464,17 @@ func (s *Server) serve(ctx context.Context, lis net.Listener, handler http.Handl
 func (s *Server) serveTLS(ctx context.Context, lis net.Listener, handler http.Handler) error {
 	conf := s.conf
 
-	tlsCert, err := tls.LoadX509KeyPair(conf.TLSCert, conf.TLSKey)
+	certs, err := newCertReloader(conf.TLSCert, conf.TLSKey, conf.TLSClientCA)
 	if err != nil {
-		return fmt.Errorf("failed to load TLS public/private key pair: %w", err)
+		return err
 	}
 
 	hs := &http.Server{
-		Handler: handler,
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{tlsCert},
-			NextProtos:   []string{"h2"},
-		},
+		Handler:   handler,
+		TLSConfig: certs.tlsConfig(),
 	}
 
-	log.Info().Str("network", conf.Network).Str("address", conf.Address).Bool("TLS", conf.TLSEnabled).Msg("server listening")
+	log.Info().Str("network", conf.Network).Str("address", conf.Address).Bool("TLS", conf.TLSEnabled).Bool("mTLS", conf.TLSClientCA != "").Msg("server listening")
 
 	idleConnsClosed := make(chan struct{})
 	go func() {
@@ -196,7 +482,7 @@ func (s *Server) serveTLS(ctx context.Context, lis net.Listener, handler http.Ha
 		close(idleConnsClosed)
 	}()
 
-	s.health.Resume()
+	s.resumeHealth()
 	err = hs.Serve(tls.NewListener(lis, hs.TLSConfig))
 	<-idleConnsClosed
 	return err
@@ -219,12 +505,20 @@ func (s *Server) serveInsecure(ctx context.Context, lis net.Listener, handler ht
 		close(idleConnsClosed)
 	}()
 
-	s.health.Resume()
+	s.resumeHealth()
 	err := h1s.Serve(lis)
 	<-idleConnsClosed
 	return err
 }
 
+// resumeHealth reports SERVING on the health service for all the services,
+// unless the server is not ready yet.
+func (s *Server) resumeHealth() {
+	if s.readiness.ready.Load() {
+		s.health.Resume()
+	}
+}
+
 const shutdownTimeout = 10 * time.Second
 
 // shutDownServerWhenContextIsDone shuts down the server when the context is done.
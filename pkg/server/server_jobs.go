@@ -0,0 +1,486 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	jobsv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/jobs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultJobTTL is the default time the jobs are kept once done.
+	DefaultJobTTL = time.Hour
+	// DefaultMaxJobQueueLength is the default maximum number of jobs waiting
+	// for a worker.
+	DefaultMaxJobQueueLength = 100
+
+	// jobCallbackAttempts is the maximum number of attempts to deliver a job
+	// to its callback URL.
+	jobCallbackAttempts = 3
+	// jobCallbackTimeout is the timeout of each attempt to deliver a job to
+	// its callback URL.
+	jobCallbackTimeout = 10 * time.Second
+)
+
+// JobsConfig is the configuration of the jobs service.
+type JobsConfig struct {
+	// Workers is the maximum number of jobs running at the same time
+	// (default 1).
+	Workers int
+	// MaxQueueLength is the maximum number of jobs waiting for a worker; the
+	// exceeding submissions fail with ResourceExhausted (default
+	// DefaultMaxJobQueueLength).
+	MaxQueueLength int
+	// TTL is the time the jobs are kept once done, after which they are
+	// deleted along with their results (default DefaultJobTTL).
+	TTL time.Duration
+	// CallbackHosts are the hosts, such as "hooks.example.com", to which the
+	// jobs can be delivered by callback URL; the callback URLs are refused if
+	// empty. Whatever the host, the callbacks are never delivered to
+	// loopback, private or link-local addresses.
+	CallbackHosts []string
+}
+
+// serverForJobs is a server that provides gRPC and HTTP/2 APIs to run the
+// task calls as asynchronous jobs, for the calls taking longer than the
+// clients, or the proxies in between, can wait for.
+type serverForJobs struct {
+	jobsv1.UnimplementedJobServiceServer
+	conf JobsConfig
+	// methods are the unary task methods, by full name.
+	methods map[string]unaryMethod
+	// workers is the semaphore bounding the running jobs.
+	workers chan struct{}
+	// callbackHosts is the set of the lowercase CallbackHosts.
+	callbackHosts map[string]bool
+	callbacks     *http.Client
+	now           func() time.Time
+
+	mu sync.Mutex
+	// ctx is the context of the server, whose end stops the delivery of the
+	// jobs to their callback URLs.
+	ctx     context.Context
+	jobs    map[string]*job
+	seq     uint64
+	pending int
+}
+
+// job is a job of serverForJobs, whose fields are guarded by its mutex.
+type job struct {
+	// seq is the order of submission.
+	seq uint64
+	// owner is the name of the API client which submitted the job, empty
+	// without authentication.
+	owner  string
+	job    *jobsv1.Job
+	cancel context.CancelFunc
+}
+
+// NewServerForJobs returns a RequestHandler serving the jobs API, whose jobs
+// call the unary methods of the services of tasks, in-process. The task
+// calls of the jobs are subject to the same limits as the other requests,
+// except for RequestTimeout. With API keys, the jobs are only visible to the
+// client which submitted them.
+func NewServerForJobs(tasks RequestHandler, conf JobsConfig) (RequestHandler, error) {
+	if conf.Workers <= 0 {
+		conf.Workers = 1
+	}
+	if conf.MaxQueueLength <= 0 {
+		conf.MaxQueueLength = DefaultMaxJobQueueLength
+	}
+	if conf.TTL <= 0 {
+		conf.TTL = DefaultJobTTL
+	}
+
+	methods := make(methodRecorder)
+	if err := tasks.RegisterServer(methods); err != nil {
+		return nil, fmt.Errorf("failed to record the task methods: %w", err)
+	}
+	callbackHosts := make(map[string]bool, len(conf.CallbackHosts))
+	for _, host := range conf.CallbackHosts {
+		callbackHosts[strings.ToLower(host)] = true
+	}
+	return &serverForJobs{
+		conf:          conf,
+		methods:       methods,
+		workers:       make(chan struct{}, conf.Workers),
+		callbackHosts: callbackHosts,
+		callbacks:     newCallbackClient(),
+		now:           time.Now,
+		ctx:           context.Background(),
+		jobs:          make(map[string]*job),
+	}, nil
+}
+
+// newCallbackClient returns the HTTP client delivering the jobs to their
+// callback URLs. It refuses to connect to the addresses which are not public
+// when dialing, so that a host name resolving to another address than when
+// the URL was submitted cannot get around it, and does not follow redirects.
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{Timeout: jobCallbackTimeout, Control: callbackDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		Timeout:   jobCallbackTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// callbackDialControl refuses the connections to the loopback, private,
+// link-local, multicast and unspecified addresses.
+func callbackDialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("callback address %s is not public", ip)
+	}
+	return nil
+}
+
+func (s *serverForJobs) RegisterServer(r grpc.ServiceRegistrar) error {
+	jobsv1.RegisterJobServiceServer(r, s)
+	return nil
+}
+
+// RegisterHandlerServer registers the HTTP handlers, the given context being
+// the one of the server, whose end stops the delivery of the callbacks.
+func (s *serverForJobs) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+	return jobsv1.RegisterJobServiceHandlerServer(ctx, mux, s)
+}
+
+// SubmitJob handles the SubmitJob request.
+//
+// The job keeps the values of the context of the request, such as its
+// metadata and input limits, but not its deadline.
+func (s *serverForJobs) SubmitJob(ctx context.Context, req *jobsv1.SubmitJobRequest) (*jobsv1.Job, error) {
+	method, ok := s.methods[req.GetMethod()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown unary task method %q", req.GetMethod())
+	}
+	if req.GetRequest() == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+	taskReq, err := req.GetRequest().UnmarshalNew()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if name := taskReq.ProtoReflect().Descriptor().FullName(); name != method.input {
+		return nil, status.Errorf(codes.InvalidArgument, "method %s expects a %s request, not %s", req.GetMethod(), method.input, name)
+	}
+	// the request is packed, so it escaped the validation of the admission
+	if a, ok := ctx.Value(admissionKey{}).(*admission); ok {
+		if err := a.validate(taskReq); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateCallbackURL(req.GetCallbackUrl()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+	if s.pending >= s.conf.MaxQueueLength {
+		return nil, resourceExhausted(ReasonQueueFull, "too many jobs waiting to run")
+	}
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.seq++
+	j := &job{
+		seq:   s.seq,
+		owner: clientName(ctx),
+		job: &jobsv1.Job{
+			Id:          newRandomID(),
+			Method:      req.GetMethod(),
+			State:       jobsv1.Job_PENDING,
+			CreateTime:  timestamppb.New(s.now()),
+			CallbackUrl: req.GetCallbackUrl(),
+		},
+		cancel: cancel,
+	}
+	s.jobs[j.job.GetId()] = j
+	s.pending++
+
+	go s.run(jobCtx, j, method, taskReq)
+
+	return proto.Clone(j.job).(*jobsv1.Job), nil
+}
+
+// GetJob handles the GetJob request.
+func (s *serverForJobs) GetJob(ctx context.Context, req *jobsv1.GetJobRequest) (*jobsv1.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(j.job).(*jobsv1.Job), nil
+}
+
+// CancelJob handles the CancelJob request. A pending job is canceled right
+// away, while a running job is canceled as soon as its task notices that
+// its context is done. Canceling a job already done has no effect.
+func (s *serverForJobs) CancelJob(ctx context.Context, req *jobsv1.CancelJobRequest) (*jobsv1.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	j.cancel()
+	if j.job.GetState() == jobsv1.Job_PENDING {
+		s.finish(j, nil, context.Canceled)
+	}
+	return proto.Clone(j.job).(*jobsv1.Job), nil
+}
+
+// ListJobs handles the ListJobs request, listing the jobs of the client.
+func (s *serverForJobs) ListJobs(ctx context.Context, req *jobsv1.ListJobsRequest) (*jobsv1.ListJobsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired()
+	owner := clientName(ctx)
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		if j.owner != owner {
+			continue
+		}
+		if req.GetState() == jobsv1.Job_STATE_UNSPECIFIED || j.job.GetState() == req.GetState() {
+			jobs = append(jobs, j)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].seq < jobs[k].seq })
+
+	resp := &jobsv1.ListJobsResponse{Jobs: make([]*jobsv1.Job, len(jobs))}
+	for i, j := range jobs {
+		resp.Jobs[i] = proto.Clone(j.job).(*jobsv1.Job)
+	}
+	return resp, nil
+}
+
+// run runs the job as soon as a worker is free.
+func (s *serverForJobs) run(ctx context.Context, j *job, method unaryMethod, req proto.Message) {
+	defer j.cancel()
+
+	select {
+	case s.workers <- struct{}{}:
+		defer func() { <-s.workers }()
+	case <-ctx.Done():
+		return // canceled while pending
+	}
+
+	s.mu.Lock()
+	if j.job.GetState() != jobsv1.Job_PENDING {
+		s.mu.Unlock()
+		return
+	}
+	s.pending--
+	j.job.State = jobsv1.Job_RUNNING
+	j.job.StartTime = timestamppb.New(s.now())
+	s.mu.Unlock()
+
+	resp, err := method.handler(method.impl, ctx, func(in any) error {
+		proto.Merge(in.(proto.Message), req)
+		return nil
+	}, nil)
+	if ctx.Err() != nil {
+		// the tasks may return what they computed before the cancellation
+		err = context.Canceled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finish(j, resp, err)
+}
+
+// finish records the outcome of the job, delivering it to the callback URL,
+// if any. It must be called with the mutex held.
+func (s *serverForJobs) finish(j *job, resp any, err error) {
+	switch j.job.GetState() {
+	case jobsv1.Job_PENDING:
+		s.pending--
+	case jobsv1.Job_RUNNING:
+	default:
+		return // already done
+	}
+
+	now := s.now()
+	j.job.EndTime = timestamppb.New(now)
+	j.job.ExpireTime = timestamppb.New(now.Add(s.conf.TTL))
+	if err == nil {
+		if j.job.Result, err = jobResponse(resp); err == nil {
+			j.job.State = jobsv1.Job_SUCCEEDED
+		}
+	}
+	if err != nil {
+		j.job.State = jobsv1.Job_FAILED
+		if errors.Is(err, context.Canceled) {
+			j.job.State = jobsv1.Job_CANCELED
+		}
+		j.job.Result = &jobsv1.Job_Error{Error: status.Convert(statusError(err)).Proto()}
+	}
+
+	if j.job.GetCallbackUrl() != "" {
+		go s.callBack(s.ctx, proto.Clone(j.job).(*jobsv1.Job))
+	}
+}
+
+// jobResponse packs the response of a task method as the result of a job.
+func jobResponse(resp any) (*jobsv1.Job_Response, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", resp)
+	}
+	packed, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &jobsv1.Job_Response{Response: packed}, nil
+}
+
+// callBack delivers the job to its callback URL, as JSON, retrying a few
+// times on failure until the context is done.
+func (s *serverForJobs) callBack(ctx context.Context, j *jobsv1.Job) {
+	body, err := protojson.Marshal(j)
+	if err != nil {
+		log.Err(err).Str("job", j.GetId()).Msg("failed to marshal job for callback")
+		return
+	}
+	for attempt := 1; attempt <= jobCallbackAttempts; attempt++ {
+		if attempt > 1 {
+			t := time.NewTimer(time.Duration(attempt-1) * time.Second)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				log.Warn().Str("job", j.GetId()).Msg("server shutting down, job not delivered to callback URL")
+				return
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.GetCallbackUrl(), bytes.NewReader(body))
+		if err != nil {
+			log.Err(err).Str("job", j.GetId()).Msg("failed to create callback request")
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.callbacks.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode/100 == 2 {
+				return
+			}
+			err = fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		log.Warn().Err(err).Str("job", j.GetId()).Int("attempt", attempt).Msg("failed to deliver job to callback URL")
+	}
+}
+
+// get returns the job with the given ID, failing with NotFound if it does
+// not exist, has expired or was submitted by another client than the one of
+// the context. It must be called with the mutex held.
+func (s *serverForJobs) get(ctx context.Context, id string) (*job, error) {
+	s.purgeExpired()
+	j, ok := s.jobs[id]
+	if !ok || j.owner != clientName(ctx) {
+		return nil, status.Errorf(codes.NotFound, "job %q not found", id)
+	}
+	return j, nil
+}
+
+// purgeExpired deletes the jobs done for longer than the TTL. It must be
+// called with the mutex held.
+func (s *serverForJobs) purgeExpired() {
+	now := s.now()
+	for id, j := range s.jobs {
+		if expire := j.job.GetExpireTime(); expire != nil && now.After(expire.AsTime()) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// validateCallbackURL returns an InvalidArgument error if the callback URL is
+// neither empty nor an absolute http or https URL to one of the callback
+// hosts.
+func (s *serverForJobs) validateCallbackURL(callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid callback URL %q: an absolute http or https URL is required", callbackURL)
+	}
+	if len(s.callbackHosts) == 0 {
+		return status.Error(codes.InvalidArgument, "invalid callback URL: callbacks are disabled")
+	}
+	if !s.callbackHosts[strings.ToLower(u.Hostname())] {
+		return status.Errorf(codes.InvalidArgument, "invalid callback URL %q: host %q is not allowed", callbackURL, u.Hostname())
+	}
+	return nil
+}
+
+// unaryMethod is a unary method of a gRPC service, which can be called
+// in-process.
+type unaryMethod struct {
+	impl    any
+	handler func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error)
+	// input is the type of the request messages.
+	input protoreflect.FullName
+}
+
+// methodRecorder is a grpc.ServiceRegistrar recording the unary methods of
+// the services, by full name (e.g. "/textencoding.v1.TextEncodingService/Encode").
+type methodRecorder map[string]unaryMethod
+
+func (r methodRecorder) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	for _, m := range desc.Methods {
+		method := unaryMethod{impl: impl, handler: m.Handler}
+		// the handlers decode the request before anything else, so the
+		// type of the request is known without calling the method
+		_, _ = m.Handler(impl, context.Background(), func(in any) error {
+			if msg, ok := in.(proto.Message); ok {
+				method.input = msg.ProtoReflect().Descriptor().FullName()
+			}
+			return errors.New("request type recorded")
+		}, nil)
+		r["/"+desc.ServiceName+"/"+m.MethodName] = method
+	}
+}
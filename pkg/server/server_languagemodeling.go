@@ -16,11 +16,11 @@ import (
 // serverForLanguageModeling is a server that provides gRPC and HTTP/2 APIs for Language Modeling task.
 type serverForLanguageModeling struct {
 	langaugemodelingnv1.UnimplementedLanguageModelingServiceServer
-	predictor languagemodeling.Interface
+	predictors modelResolver[languagemodeling.Interface]
 }
 
 func NewServerForLanguageModeling(predictor languagemodeling.Interface) RequestHandler {
-	return &serverForLanguageModeling{predictor: predictor}
+	return &serverForLanguageModeling{predictors: singleModel(predictor)}
 }
 
 func (s *serverForLanguageModeling) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -34,13 +34,55 @@ func (s *serverForLanguageModeling) RegisterHandlerServer(ctx context.Context, m
 
 // Predict handles the Predict request.
 func (s *serverForLanguageModeling) Predict(ctx context.Context, req *langaugemodelingnv1.LanguageModelingRequest) (*langaugemodelingnv1.LanguageModelingResponse, error) {
-	result, err := s.predictor.Predict(ctx, req.GetInput(), languagemodeling.Parameters{
+	predictor, release, err := s.predictors(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := predictor.Predict(ctx, req.GetInput(), languagemodeling.Parameters{
 		K: int(req.GetParameters().GetK()),
 	})
 	if err != nil {
 		return nil, err
 	}
+	return languageModelingResponse(result), nil
+}
+
+// BatchPredict handles the BatchPredict request.
+func (s *serverForLanguageModeling) BatchPredict(ctx context.Context, req *langaugemodelingnv1.BatchLanguageModelingRequest) (*langaugemodelingnv1.BatchLanguageModelingResponse, error) {
+	predictor, release, err := s.predictors(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	params := languagemodeling.Parameters{
+		K: int(req.GetParameters().GetK()),
+	}
+	results, errs, err := serveBatch(ctx, req.GetInputs(), nil,
+		func(ctx context.Context, text string) (languagemodeling.Response, error) {
+			return predictor.Predict(ctx, text, params)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
+	resp := &langaugemodelingnv1.BatchLanguageModelingResponse{
+		Results: make([]*langaugemodelingnv1.BatchLanguageModelingResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &langaugemodelingnv1.BatchLanguageModelingResult{Result: &langaugemodelingnv1.BatchLanguageModelingResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &langaugemodelingnv1.BatchLanguageModelingResult{Result: &langaugemodelingnv1.BatchLanguageModelingResult_Response{Response: languageModelingResponse(result)}}
+	}
+	return resp, nil
+}
+
+func languageModelingResponse(result languagemodeling.Response) *langaugemodelingnv1.LanguageModelingResponse {
 	tokens := make([]*langaugemodelingnv1.Token, len(result.Tokens))
 	for i, token := range result.Tokens {
 		tokens[i] = &langaugemodelingnv1.Token{
@@ -50,8 +92,7 @@ func (s *serverForLanguageModeling) Predict(ctx context.Context, req *langaugemo
 			End:    int32(token.End),
 		}
 	}
-	resp := &langaugemodelingnv1.LanguageModelingResponse{
+	return &langaugemodelingnv1.LanguageModelingResponse{
 		Tokens: tokens,
 	}
-	return resp, nil
 }
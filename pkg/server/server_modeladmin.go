@@ -0,0 +1,168 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	modeladminv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/modeladmin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ModelLoader loads the model for the given task, running the whole
+// download, conversion and loading pipeline (see tasks.Load).
+type ModelLoader func(ctx context.Context, task, model string) (any, error)
+
+// Tasks lists the names of the supported tasks (see TaskOf).
+var Tasks = []string{
+	"text-generation",
+	"zero-shot-classification",
+	"question-answering",
+	"text-classification",
+	"text-encoding",
+	"token-classification",
+	"language-modeling",
+}
+
+// serverForModelAdmin is a server that provides gRPC and HTTP/2 APIs to
+// manage the models of a Registry at runtime.
+type serverForModelAdmin struct {
+	modeladminv1.UnimplementedModelAdminServiceServer
+	registry *Registry
+	load     ModelLoader
+}
+
+// NewServerForModelAdmin returns a RequestHandler serving the model
+// management API for the given registry. New models are loaded with load.
+func NewServerForModelAdmin(registry *Registry, load ModelLoader) RequestHandler {
+	return &serverForModelAdmin{registry: registry, load: load}
+}
+
+func (s *serverForModelAdmin) RegisterServer(r grpc.ServiceRegistrar) error {
+	modeladminv1.RegisterModelAdminServiceServer(r, s)
+	return nil
+}
+
+func (s *serverForModelAdmin) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	return modeladminv1.RegisterModelAdminServiceHandlerServer(ctx, mux, s)
+}
+
+// ListModels handles the ListModels request.
+func (s *serverForModelAdmin) ListModels(_ context.Context, _ *modeladminv1.ListModelsRequest) (*modeladminv1.ListModelsResponse, error) {
+	infos := s.registry.Models()
+	models := make([]*modeladminv1.Model, len(infos))
+	for i, info := range infos {
+		models[i] = modelInfo(info)
+	}
+	return &modeladminv1.ListModelsResponse{Models: models}, nil
+}
+
+// LoadModel handles the LoadModel request.
+func (s *serverForModelAdmin) LoadModel(ctx context.Context, req *modeladminv1.LoadModelRequest) (*modeladminv1.LoadModelResponse, error) {
+	if req.GetName() == "" || req.GetModel() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and model are required")
+	}
+	if !slices.Contains(Tasks, req.GetTask()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid task %q", req.GetTask())
+	}
+	if _, err := s.registry.Get(req.GetName()); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "model %q already exists", req.GetName())
+	}
+
+	m, err := s.loadModel(ctx, req.GetTask(), req.GetModel())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registry.AddVersion(req.GetName(), req.GetModel(), m); err != nil {
+		return nil, registryError(err)
+	}
+	log.Info().Str("name", req.GetName()).Str("task", req.GetTask()).Str("model", req.GetModel()).Msg("model loaded")
+
+	info, err := s.registry.Get(req.GetName())
+	if err != nil {
+		return nil, registryError(err)
+	}
+	return &modeladminv1.LoadModelResponse{Model: modelInfo(info)}, nil
+}
+
+// UnloadModel handles the UnloadModel request.
+func (s *serverForModelAdmin) UnloadModel(ctx context.Context, req *modeladminv1.UnloadModelRequest) (*modeladminv1.UnloadModelResponse, error) {
+	if err := s.registry.Remove(ctx, req.GetName()); errors.Is(err, ErrModelDraining) {
+		log.Warn().Err(err).Str("name", req.GetName()).Msg("model unloaded before its requests were served")
+	} else if err != nil {
+		return nil, registryError(err)
+	}
+	log.Info().Str("name", req.GetName()).Msg("model unloaded")
+	return &modeladminv1.UnloadModelResponse{}, nil
+}
+
+// SwapModel handles the SwapModel request.
+func (s *serverForModelAdmin) SwapModel(ctx context.Context, req *modeladminv1.SwapModelRequest) (*modeladminv1.SwapModelResponse, error) {
+	if req.GetModel() == "" {
+		return nil, status.Error(codes.InvalidArgument, "model is required")
+	}
+	info, err := s.registry.Get(req.GetName())
+	if err != nil {
+		return nil, registryError(err)
+	}
+
+	m, err := s.loadModel(ctx, info.Task, req.GetModel())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registry.SwapVersion(ctx, req.GetName(), req.GetModel(), m); errors.Is(err, ErrModelDraining) {
+		log.Warn().Err(err).Str("name", req.GetName()).Msg("model swapped before the requests on the old one were served")
+	} else if err != nil {
+		return nil, registryError(err)
+	}
+	log.Info().Str("name", req.GetName()).Str("task", info.Task).Str("model", req.GetModel()).Msg("model swapped")
+
+	info, err = s.registry.Get(req.GetName())
+	if err != nil {
+		return nil, registryError(err)
+	}
+	return &modeladminv1.SwapModelResponse{Model: modelInfo(info)}, nil
+}
+
+// loadModel loads the model and checks that it fulfills the given task.
+func (s *serverForModelAdmin) loadModel(ctx context.Context, task, model string) (any, error) {
+	m, err := s.load(ctx, task, model)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to load model %q: %v", model, err)
+	}
+	if t := TaskOf(m); t != task {
+		return nil, status.Errorf(codes.InvalidArgument, "model %q fulfills task %q, not %q", model, t, task)
+	}
+	return m, nil
+}
+
+// registryError converts an error returned by the Registry to a gRPC status error.
+func registryError(err error) error {
+	switch {
+	case errors.Is(err, ErrModelNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrModelExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+func modelInfo(info ModelInfo) *modeladminv1.Model {
+	return &modeladminv1.Model{
+		Name:             info.Name,
+		Task:             info.Task,
+		InFlightRequests: info.InFlight,
+		Version:          info.Version,
+	}
+}
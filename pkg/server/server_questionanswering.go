@@ -16,11 +16,11 @@ import (
 // serverForQuestionAnswering is a server that provides gRPC and HTTP/2 APIs for Interface task.
 type serverForQuestionAnswering struct {
 	questionansweringv1.UnimplementedQuestionAnsweringServiceServer
-	engine questionanswering.Interface
+	engines modelResolver[questionanswering.Interface]
 }
 
 func NewServerForQuestionAnswering(engine questionanswering.Interface) RequestHandler {
-	return &serverForQuestionAnswering{engine: engine}
+	return &serverForQuestionAnswering{engines: singleModel(engine)}
 }
 
 func (s *serverForQuestionAnswering) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -34,18 +34,68 @@ func (s *serverForQuestionAnswering) RegisterHandlerServer(ctx context.Context,
 
 // ExtractAnswer handles the Answer request.
 func (s *serverForQuestionAnswering) ExtractAnswer(ctx context.Context, req *questionansweringv1.AnswerRequest) (*questionansweringv1.AnswerResponse, error) {
-	params := req.GetOptions()
-	opts := &questionanswering.Options{
+	engine, release, err := s.engines(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := engine.ExtractAnswer(ctx, req.GetQuestion(), req.GetPassage(), answerOptions(req.GetOptions()))
+	if err != nil {
+		return nil, err
+	}
+	return answerResponse(result), nil
+}
+
+// BatchExtractAnswer handles the BatchExtractAnswer request.
+func (s *serverForQuestionAnswering) BatchExtractAnswer(ctx context.Context, req *questionansweringv1.BatchAnswerRequest) (*questionansweringv1.BatchAnswerResponse, error) {
+	engine, release, err := s.engines(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	opts := answerOptions(req.GetOptions())
+	results, errs, err := serveBatch(ctx, req.GetInputs(),
+		func(ctx context.Context, inputs []*questionansweringv1.AnswerInput) ([]questionanswering.Response, error) {
+			batch := make([]questionanswering.Input, len(inputs))
+			for i, input := range inputs {
+				batch[i] = questionanswering.Input{Question: input.GetQuestion(), Passage: input.GetPassage()}
+			}
+			return engine.ExtractAnswerBatch(ctx, batch, opts)
+		},
+		func(ctx context.Context, input *questionansweringv1.AnswerInput) (questionanswering.Response, error) {
+			return engine.ExtractAnswer(ctx, input.GetQuestion(), input.GetPassage(), opts)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &questionansweringv1.BatchAnswerResponse{
+		Results: make([]*questionansweringv1.BatchAnswerResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &questionansweringv1.BatchAnswerResult{Result: &questionansweringv1.BatchAnswerResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &questionansweringv1.BatchAnswerResult{Result: &questionansweringv1.BatchAnswerResult_Response{Response: answerResponse(result)}}
+	}
+	return resp, nil
+}
+
+// answerOptions converts the request options to questionanswering.Options.
+func answerOptions(params *questionansweringv1.QuestionAnsweringOptions) *questionanswering.Options {
+	return &questionanswering.Options{
 		MaxAnswers:      int(params.GetMaxAnswers()),
 		MaxAnswerLength: int(params.GetMaxAnswersLen()),
 		MinScore:        params.GetMinScore(),
 		MaxCandidates:   int(params.GetMaxCandidates()),
 	}
+}
 
-	result, err := s.engine.ExtractAnswer(ctx, req.GetQuestion(), req.GetPassage(), opts)
-	if err != nil {
-		return nil, err
-	}
+func answerResponse(result questionanswering.Response) *questionansweringv1.AnswerResponse {
 	answers := make([]*questionansweringv1.Answer, len(result.Answers))
 	for i, answer := range result.Answers {
 		answers[i] = &questionansweringv1.Answer{
@@ -55,8 +105,7 @@ func (s *serverForQuestionAnswering) ExtractAnswer(ctx context.Context, req *que
 			End:   int64(answer.End),
 		}
 	}
-	resp := &questionansweringv1.AnswerResponse{
+	return &questionansweringv1.AnswerResponse{
 		Answers: answers,
 	}
-	return resp, nil
 }
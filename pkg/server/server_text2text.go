@@ -6,22 +6,28 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
 	textgenerationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textgeneration/v1"
 	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
 	"github.com/yinziyang/cybertron/pkg/utils/nullable"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // serverForTextGeneration is a server that provides gRPC and HTTP/2 APIs for Interface task.
 type serverForTextGeneration struct {
 	textgenerationv1.UnimplementedTextGenerationServiceServer
-	generator textgeneration.Interface
+	generators modelResolver[textgeneration.Interface]
 }
 
 func NewServerForTextGeneration(generator textgeneration.Interface) RequestHandler {
-	return &serverForTextGeneration{generator: generator}
+	return &serverForTextGeneration{generators: singleModel(generator)}
 }
 
 func (s *serverForTextGeneration) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -30,27 +36,187 @@ func (s *serverForTextGeneration) RegisterServer(r grpc.ServiceRegistrar) error
 }
 
 func (s *serverForTextGeneration) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
-	return textgenerationv1.RegisterTextGenerationServiceHandlerServer(ctx, mux, s)
+	if err := textgenerationv1.RegisterTextGenerationServiceHandlerServer(ctx, mux, s); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodPost, "/v1/generate:stream", s.handleGenerateStream(mux))
 }
 
 // Generate handles the Generate request.
 func (s *serverForTextGeneration) Generate(ctx context.Context, req *textgenerationv1.GenerateRequest) (*textgenerationv1.GenerateResponse, error) {
-	opts := req.GetParameters()
-	if opts == nil {
-		opts = &textgenerationv1.TextGenerationParameters{}
-	}
-	result, err := s.generator.Generate(ctx, req.GetInput(), &textgeneration.Options{
-		Temperature: nullable.Any(opts.Temperature),
-		Sample:      nullable.Any(opts.DoSample),
-		TopK:        nullable.Int(opts.TopK),
-		TopP:        nullable.Any(opts.TopP),
-	})
+	generator, release, err := s.generators(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := generator.Generate(ctx, req.GetInput(), generationOptions(req.GetParameters()))
+	if err != nil {
+		return nil, err
+	}
+	return generateResponse(result), nil
+}
+
+// BatchGenerate handles the BatchGenerate request.
+func (s *serverForTextGeneration) BatchGenerate(ctx context.Context, req *textgenerationv1.BatchGenerateRequest) (*textgenerationv1.BatchGenerateResponse, error) {
+	generator, release, err := s.generators(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	opts := generationOptions(req.GetParameters())
+	results, errs, err := serveBatch(ctx, req.GetInputs(), nil,
+		func(ctx context.Context, text string) (textgeneration.Response, error) {
+			return generator.Generate(ctx, text, opts)
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
-	resp := &textgenerationv1.GenerateResponse{
+
+	resp := &textgenerationv1.BatchGenerateResponse{
+		Results: make([]*textgenerationv1.BatchGenerateResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &textgenerationv1.BatchGenerateResult{Result: &textgenerationv1.BatchGenerateResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &textgenerationv1.BatchGenerateResult{Result: &textgenerationv1.BatchGenerateResult_Response{Response: generateResponse(result)}}
+	}
+	return resp, nil
+}
+
+// GenerateStream handles the GenerateStream request.
+func (s *serverForTextGeneration) GenerateStream(req *textgenerationv1.GenerateRequest, stream textgenerationv1.TextGenerationService_GenerateStreamServer) error {
+	generator, release, err := s.generators(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	chunks, err := generator.GenerateStream(stream.Context(), req.GetInput(), generationOptions(req.GetParameters()))
+	if err != nil {
+		return err
+	}
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		if err := stream.Send(generateStreamResponse(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleGenerateStream returns an HTTP handler serving GenerateStream as
+// server-sent events. Each event carries a GenerateStreamResponse; a failure
+// after the stream has started is reported with an "error" event carrying
+// the google.rpc.Status.
+func (s *serverForTextGeneration) handleGenerateStream(mux *runtime.ServeMux) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		inbound, outbound := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateIncomingContext(r.Context(), mux, r,
+			"/textgeneration.v1.TextGenerationService/GenerateStream", runtime.WithHTTPPathPattern("/v1/generate:stream"))
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		req := &textgenerationv1.GenerateRequest{}
+		if err := inbound.NewDecoder(r.Body).Decode(req); err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.Internal, "streaming unsupported"))
+			return
+		}
+
+		generator, release, err := s.generators(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		defer release()
+
+		chunks, err := generator.GenerateStream(ctx, req.GetInput(), generationOptions(req.GetParameters()))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for chunk := range chunks {
+			event, msg := "", proto.Message(nil)
+			if chunk.Err != nil {
+				st := status.Convert(statusError(chunk.Err))
+				event, msg = "error", st.Proto()
+				setHTTPCallCode(ctx, st.Code())
+				logError(ctx, st.Err())
+			} else {
+				msg = generateStreamResponse(chunk)
+			}
+			data, err := outbound.Marshal(msg)
+			if err != nil {
+				log.Err(err).Msg("failed to marshal server-sent event")
+				return
+			}
+			if event != "" {
+				fmt.Fprintf(w, "event: %s\n", event)
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// generationOptions converts the request parameters to textgeneration.Options.
+func generationOptions(params *textgenerationv1.TextGenerationParameters) *textgeneration.Options {
+	if params == nil {
+		params = &textgenerationv1.TextGenerationParameters{}
+	}
+	return &textgeneration.Options{
+		Temperature: nullable.Any(params.Temperature),
+		Sample:      nullable.Any(params.DoSample),
+		TopK:        nullable.Int(params.TopK),
+		TopP:        nullable.Any(params.TopP),
+
+		NumBeams:           nullable.Int(params.NumBeams),
+		MinLength:          nullable.Int(params.MinLength),
+		MaxLength:          nullable.Int(params.MaxLength),
+		MaxNewTokens:       nullable.Int(params.MaxNewTokens),
+		LengthPenalty:      nullable.Any(params.LengthPenalty),
+		EarlyStopping:      nullable.Any(params.EarlyStopping),
+		NoRepeatNGramSize:  nullable.Int(params.NoRepeatNgramSize),
+		NumReturnSequences: nullable.Int(params.NumReturnSequences),
+		StopSequences:      params.GetStopSequences(),
+		BadWords:           params.GetBadWords(),
+	}
+}
+
+func generateResponse(result textgeneration.Response) *textgenerationv1.GenerateResponse {
+	return &textgenerationv1.GenerateResponse{
 		Texts:  result.Texts,
 		Scores: result.Scores,
 	}
-	return resp, nil
+}
+
+func generateStreamResponse(chunk textgeneration.Chunk) *textgenerationv1.GenerateStreamResponse {
+	resp := &textgenerationv1.GenerateStreamResponse{
+		Text:  chunk.Text,
+		Final: chunk.Final,
+	}
+	if chunk.Final {
+		resp.Result = generateResponse(chunk.Response)
+	}
+	return resp
 }
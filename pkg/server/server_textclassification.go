@@ -16,11 +16,11 @@ import (
 // serverForTextClassification is a server that provides gRPC and HTTP/2 APIs for Text Classification task.
 type serverForTextClassification struct {
 	textclassificationv1.UnimplementedTextClassificationServiceServer
-	classifier textclassification.Interface
+	classifiers modelResolver[textclassification.Interface]
 }
 
 func NewServerForTextClassification(classifier textclassification.Interface) RequestHandler {
-	return &serverForTextClassification{classifier: classifier}
+	return &serverForTextClassification{classifiers: singleModel(classifier)}
 }
 
 func (s *serverForTextClassification) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -34,13 +34,48 @@ func (s *serverForTextClassification) RegisterHandlerServer(ctx context.Context,
 
 // Classify handles the Classify request.
 func (s *serverForTextClassification) Classify(ctx context.Context, req *textclassificationv1.ClassifyRequest) (*textclassificationv1.ClassifyResponse, error) {
-	result, err := s.classifier.Classify(ctx, req.GetInput())
+	classifier, release, err := s.classifiers(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	resp := &textclassificationv1.ClassifyResponse{
+	defer release()
+
+	result, err := classifier.Classify(ctx, req.GetInput())
+	if err != nil {
+		return nil, err
+	}
+	return textClassifyResponse(result), nil
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *serverForTextClassification) BatchClassify(ctx context.Context, req *textclassificationv1.BatchClassifyRequest) (*textclassificationv1.BatchClassifyResponse, error) {
+	classifier, release, err := s.classifiers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	results, errs, err := serveBatch(ctx, req.GetInputs(), classifier.ClassifyBatch, classifier.Classify)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &textclassificationv1.BatchClassifyResponse{
+		Results: make([]*textclassificationv1.BatchClassifyResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &textclassificationv1.BatchClassifyResult{Result: &textclassificationv1.BatchClassifyResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &textclassificationv1.BatchClassifyResult{Result: &textclassificationv1.BatchClassifyResult_Response{Response: textClassifyResponse(result)}}
+	}
+	return resp, nil
+}
+
+func textClassifyResponse(result textclassification.Response) *textclassificationv1.ClassifyResponse {
+	return &textclassificationv1.ClassifyResponse{
 		Labels: result.Labels,
 		Scores: result.Scores,
 	}
-	return resp, nil
 }
@@ -16,11 +16,11 @@ import (
 // serverForTextClassification is a server that provides gRPC and HTTP/2 APIs for Text Classification task.
 type serverForTextEncoding struct {
 	textencodingv1.UnimplementedTextEncodingServiceServer
-	encoder textencoding.Interface
+	encoders modelResolver[textencoding.Interface]
 }
 
 func NewServerForTextEncoding(encoder textencoding.Interface) RequestHandler {
-	return &serverForTextEncoding{encoder: encoder}
+	return &serverForTextEncoding{encoders: singleModel(encoder)}
 }
 
 func (s *serverForTextEncoding) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -34,12 +34,55 @@ func (s *serverForTextEncoding) RegisterHandlerServer(ctx context.Context, mux *
 
 // Encode handles the Encode request.
 func (s *serverForTextEncoding) Encode(ctx context.Context, req *textencodingv1.EncodingRequest) (*textencodingv1.EncodingResponse, error) {
-	result, err := s.encoder.Encode(ctx, req.GetInput(), int(req.GetPoolingStrategy()))
+	encoder, release, err := s.encoders(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	resp := &textencodingv1.EncodingResponse{
-		Vector: result.Vector.Data().F32(),
+	defer release()
+
+	result, err := encoder.Encode(ctx, req.GetInput(), int(req.GetPoolingStrategy()))
+	if err != nil {
+		return nil, err
+	}
+	return encodingResponse(result), nil
+}
+
+// BatchEncode handles the BatchEncode request.
+func (s *serverForTextEncoding) BatchEncode(ctx context.Context, req *textencodingv1.BatchEncodingRequest) (*textencodingv1.BatchEncodingResponse, error) {
+	encoder, release, err := s.encoders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	poolingStrategy := int(req.GetPoolingStrategy())
+	results, errs, err := serveBatch(ctx, req.GetInputs(),
+		func(ctx context.Context, texts []string) ([]textencoding.Response, error) {
+			return encoder.EncodeBatch(ctx, texts, poolingStrategy)
+		},
+		func(ctx context.Context, text string) (textencoding.Response, error) {
+			return encoder.Encode(ctx, text, poolingStrategy)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &textencodingv1.BatchEncodingResponse{
+		Results: make([]*textencodingv1.BatchEncodingResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &textencodingv1.BatchEncodingResult{Result: &textencodingv1.BatchEncodingResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &textencodingv1.BatchEncodingResult{Result: &textencodingv1.BatchEncodingResult_Response{Response: encodingResponse(result)}}
 	}
 	return resp, nil
 }
+
+func encodingResponse(result textencoding.Response) *textencodingv1.EncodingResponse {
+	return &textencodingv1.EncodingResponse{
+		Vector: result.Vector.Data().F32(),
+	}
+}
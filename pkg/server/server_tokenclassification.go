@@ -17,11 +17,11 @@ import (
 // serverForTextClassification is a server that provides gRPC and HTTP/2 APIs for Token Classification task.
 type serverForTokenClassification struct {
 	tokenclassificationv1.UnimplementedTokenClassificationServiceServer
-	classifier tokenclassification.Interface
+	classifiers modelResolver[tokenclassification.Interface]
 }
 
 func NewServerForTokenClassification(classifier tokenclassification.Interface) RequestHandler {
-	return &serverForTokenClassification{classifier: classifier}
+	return &serverForTokenClassification{classifiers: singleModel(classifier)}
 }
 
 func (s *serverForTokenClassification) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -35,13 +35,58 @@ func (s *serverForTokenClassification) RegisterHandlerServer(ctx context.Context
 
 // Classify handles the Classify request.
 func (s *serverForTokenClassification) Classify(ctx context.Context, req *tokenclassificationv1.ClassifyRequest) (*tokenclassificationv1.ClassifyResponse, error) {
-	result, err := s.classifier.Classify(ctx, req.GetInput(), tokenclassification.Parameters{
+	classifier, release, err := s.classifiers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := classifier.Classify(ctx, req.GetInput(), tokenclassification.Parameters{
 		AggregationStrategy: convAggregationStrategy(req.AggregationStrategy),
 	})
 	if err != nil {
 		return nil, err
 	}
+	return tokenClassifyResponse(result), nil
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *serverForTokenClassification) BatchClassify(ctx context.Context, req *tokenclassificationv1.BatchClassifyRequest) (*tokenclassificationv1.BatchClassifyResponse, error) {
+	classifier, release, err := s.classifiers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	params := tokenclassification.Parameters{
+		AggregationStrategy: convAggregationStrategy(req.AggregationStrategy),
+	}
+	results, errs, err := serveBatch(ctx, req.GetInputs(),
+		func(ctx context.Context, texts []string) ([]tokenclassification.Response, error) {
+			return classifier.ClassifyBatch(ctx, texts, params)
+		},
+		func(ctx context.Context, text string) (tokenclassification.Response, error) {
+			return classifier.Classify(ctx, text, params)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
+	resp := &tokenclassificationv1.BatchClassifyResponse{
+		Results: make([]*tokenclassificationv1.BatchClassifyResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &tokenclassificationv1.BatchClassifyResult{Result: &tokenclassificationv1.BatchClassifyResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &tokenclassificationv1.BatchClassifyResult{Result: &tokenclassificationv1.BatchClassifyResult_Response{Response: tokenClassifyResponse(result)}}
+	}
+	return resp, nil
+}
+
+func tokenClassifyResponse(result tokenclassification.Response) *tokenclassificationv1.ClassifyResponse {
 	tokens := make([]*tokenclassificationv1.Token, len(result.Tokens))
 	for i, token := range result.Tokens {
 		tokens[i] = &tokenclassificationv1.Token{
@@ -52,10 +97,9 @@ func (s *serverForTokenClassification) Classify(ctx context.Context, req *tokenc
 			End:   int32(token.End),
 		}
 	}
-	resp := &tokenclassificationv1.ClassifyResponse{
+	return &tokenclassificationv1.ClassifyResponse{
 		Tokens: tokens,
 	}
-	return resp, nil
 }
 
 func convAggregationStrategy(strategy tokenclassificationv1.ClassifyRequest_AggregationStrategy) tokenclassification.AggregationStrategy {
@@ -16,11 +16,11 @@ import (
 // serverForZeroShotClassification is a server that provides gRPC and HTTP/2 APIs for Zero-Shot Classification task.
 type serverForZeroShotClassification struct {
 	zeroshotv1.UnimplementedZeroShotServiceServer
-	classifier zeroshotclassifier.Interface
+	classifiers modelResolver[zeroshotclassifier.Interface]
 }
 
 func NewServerForZeroShotClassification(classifier zeroshotclassifier.Interface) RequestHandler {
-	return &serverForZeroShotClassification{classifier: classifier}
+	return &serverForZeroShotClassification{classifiers: singleModel(classifier)}
 }
 
 func (s *serverForZeroShotClassification) RegisterServer(r grpc.ServiceRegistrar) error {
@@ -34,20 +34,62 @@ func (s *serverForZeroShotClassification) RegisterHandlerServer(ctx context.Cont
 
 // Classify handles the Classify request.
 func (s *serverForZeroShotClassification) Classify(ctx context.Context, req *zeroshotv1.ClassifyRequest) (*zeroshotv1.ClassifyResponse, error) {
-	params := req.GetParameters()
-	candidateLabels := params.GetCandidateLabels()
-	result, err := s.classifier.Classify(ctx, req.GetInput(), zeroshotclassifier.Parameters{
-		CandidateLabels:    candidateLabels,
-		HypothesisTemplate: params.GetHypothesisTemplate(),
-		MultiLabel:         params.GetMultiLabel(),
-	})
+	classifier, release, err := s.classifiers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := classifier.Classify(ctx, req.GetInput(), zeroShotParameters(req.GetParameters()))
+	if err != nil {
+		return nil, err
+	}
+	return zeroShotClassifyResponse(result), nil
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *serverForZeroShotClassification) BatchClassify(ctx context.Context, req *zeroshotv1.BatchClassifyRequest) (*zeroshotv1.BatchClassifyResponse, error) {
+	classifier, release, err := s.classifiers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	params := zeroShotParameters(req.GetParameters())
+	results, errs, err := serveBatch(ctx, req.GetInputs(), nil,
+		func(ctx context.Context, text string) (zeroshotclassifier.Response, error) {
+			return classifier.Classify(ctx, text, params)
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := &zeroshotv1.ClassifyResponse{
+	resp := &zeroshotv1.BatchClassifyResponse{
+		Results: make([]*zeroshotv1.BatchClassifyResult, len(results)),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			resp.Results[i] = &zeroshotv1.BatchClassifyResult{Result: &zeroshotv1.BatchClassifyResult_Error{Error: batchItemError(errs[i])}}
+			continue
+		}
+		resp.Results[i] = &zeroshotv1.BatchClassifyResult{Result: &zeroshotv1.BatchClassifyResult_Response{Response: zeroShotClassifyResponse(result)}}
+	}
+	return resp, nil
+}
+
+// zeroShotParameters converts the request parameters to zeroshotclassifier.Parameters.
+func zeroShotParameters(params *zeroshotv1.ZeroShotParameters) zeroshotclassifier.Parameters {
+	return zeroshotclassifier.Parameters{
+		CandidateLabels:    params.GetCandidateLabels(),
+		HypothesisTemplate: params.GetHypothesisTemplate(),
+		MultiLabel:         params.GetMultiLabel(),
+	}
+}
+
+func zeroShotClassifyResponse(result zeroshotclassifier.Response) *zeroshotv1.ClassifyResponse {
+	return &zeroshotv1.ClassifyResponse{
 		Labels: result.Labels,
 		Scores: result.Scores,
 	}
-	return resp, nil
 }
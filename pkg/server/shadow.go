@@ -0,0 +1,440 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	textclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textclassification/v1"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	tokenclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/tokenclassification/v1"
+	zeroshotv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/zeroshot/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultShadowTimeout is the default maximum time to serve a request
+	// mirrored to the candidate of a shadow deployment.
+	DefaultShadowTimeout = 30 * time.Second
+	// DefaultMaxConcurrentShadows is the default maximum number of requests
+	// mirrored to the candidate of a shadow deployment at the same time.
+	DefaultMaxConcurrentShadows = 4
+)
+
+// ShadowConfig is the configuration of a shadow deployment (see
+// NewShadowServer).
+type ShadowConfig struct {
+	// CandidateWeight is the fraction of the requests, between 0 and 1,
+	// served by the candidate instead of the primary (A/B routing). The
+	// requests served by the candidate are not mirrored.
+	CandidateWeight float64
+	// Timeout is the maximum time to serve a mirrored request (default
+	// DefaultShadowTimeout).
+	Timeout time.Duration
+	// MaxConcurrentShadows is the maximum number of mirrored requests served
+	// at the same time; the requests served by the primary beyond that are
+	// not mirrored (default DefaultMaxConcurrentShadows).
+	MaxConcurrentShadows int
+}
+
+// NewShadowServer returns a RequestHandler serving the requests with the
+// primary handler, mirroring a copy of each one to the candidate handler,
+// such as a new checkpoint of the same model, to compare their outputs
+// before promoting it. The primary and the candidate are handlers of the
+// same task, as returned by ResolveRequestHandler; the text classification,
+// zero-shot classification, token classification and text encoding tasks
+// are supported.
+//
+// The mirrored requests are served asynchronously, after the response of
+// the primary has been returned, with the lowest priority and subject to the
+// limits of the server. Their disagreement with the primary is recorded in
+// the metrics of the server: the mismatches of the top labels, the Jaccard
+// similarity of the entities and the cosine similarity of the embeddings.
+func NewShadowServer(primary, candidate RequestHandler, conf ShadowConfig) (RequestHandler, error) {
+	if conf.CandidateWeight < 0 || conf.CandidateWeight > 1 {
+		return nil, fmt.Errorf("invalid candidate weight %g: expected a value between 0 and 1", conf.CandidateWeight)
+	}
+	sh := newShadow(conf)
+	switch p := primary.(type) {
+	case *serverForTextClassification:
+		if c, ok := candidate.(*serverForTextClassification); ok {
+			return &shadowServerForTextClassification{serverForTextClassification: p, candidate: c, shadow: sh}, nil
+		}
+	case *serverForZeroShotClassification:
+		if c, ok := candidate.(*serverForZeroShotClassification); ok {
+			return &shadowServerForZeroShotClassification{serverForZeroShotClassification: p, candidate: c, shadow: sh}, nil
+		}
+	case *serverForTokenClassification:
+		if c, ok := candidate.(*serverForTokenClassification); ok {
+			return &shadowServerForTokenClassification{serverForTokenClassification: p, candidate: c, shadow: sh}, nil
+		}
+	case *serverForTextEncoding:
+		if c, ok := candidate.(*serverForTextEncoding); ok {
+			return &shadowServerForTextEncoding{serverForTextEncoding: p, candidate: c, shadow: sh}, nil
+		}
+	default:
+		return nil, fmt.Errorf("shadow deployment not supported for request handler %T", primary)
+	}
+	return nil, fmt.Errorf("candidate request handler %T does not serve the task of %T", candidate, primary)
+}
+
+// shadow routes the requests of a shadow deployment and records the
+// disagreement between the primary and the candidate.
+type shadow struct {
+	candidateWeight float64
+	timeout         time.Duration
+	// slots is the semaphore bounding the mirrored requests in progress.
+	slots chan struct{}
+	// metrics are the metrics of the server, nil if disabled.
+	metrics *metrics
+}
+
+// shadowReporter is implemented by the request handlers of the shadow
+// deployments, recording their own metrics.
+type shadowReporter interface {
+	setShadowMetrics(*metrics)
+}
+
+func newShadow(conf ShadowConfig) *shadow {
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = DefaultShadowTimeout
+	}
+	maxShadows := conf.MaxConcurrentShadows
+	if maxShadows <= 0 {
+		maxShadows = DefaultMaxConcurrentShadows
+	}
+	return &shadow{
+		candidateWeight: conf.CandidateWeight,
+		timeout:         timeout,
+		slots:           make(chan struct{}, maxShadows),
+	}
+}
+
+func (s *shadow) setShadowMetrics(m *metrics) {
+	s.metrics = m
+}
+
+// shadowCall serves the request of the given method with the primary,
+// mirroring it to the candidate, or with the candidate, according to the
+// candidate weight.
+func shadowCall[Req proto.Message, Resp any](
+	ctx context.Context,
+	s *shadow,
+	method string,
+	req Req,
+	primary, candidate func(context.Context, Req) (Resp, error),
+	compare func(s *shadow, method string, primary, candidate Resp),
+) (Resp, error) {
+	if s.candidateWeight > 0 && rand.Float64() < s.candidateWeight {
+		s.countRouted(method, "candidate")
+		return candidate(ctx, req)
+	}
+	s.countRouted(method, "primary")
+	resp, err := primary(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		s.countRequest(method, "dropped")
+		return resp, nil
+	}
+	// the request may be modified once the call returns
+	mirrored := proto.Clone(req).(Req)
+	shadowCtx, cancelShadow := shadowContext(ctx)
+	go func() {
+		defer func() { <-s.slots }()
+		defer cancelShadow()
+		ctx, cancel := context.WithTimeout(shadowCtx, s.timeout)
+		defer cancel()
+		candidateResp, err := candidate(ctx, mirrored)
+		if err != nil {
+			log.Debug().Err(err).Str("method", method).Msg("shadow request failed")
+			s.countRequest(method, "failed")
+			return
+		}
+		s.countRequest(method, "compared")
+		compare(s, method, resp, candidateResp)
+	}()
+	return resp, nil
+}
+
+// shadowContext returns the context of a request mirrored from the given
+// one: not canceled with it, subject to the same admission, input limits and
+// timeout, with the lowest priority, so as not to delay the live requests.
+func shadowContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	shadowCtx, cancel := context.Background(), context.CancelFunc(func() {})
+	if a, ok := ctx.Value(admissionKey{}).(*admission); ok {
+		shadowCtx, cancel = limitedRequestContext(shadowCtx, a, a.conf)
+	}
+	return withPriorityHeader(shadowCtx, PriorityBatch.String()), cancel
+}
+
+func (s *shadow) countRouted(fullMethod, variant string) {
+	if s.metrics == nil {
+		return
+	}
+	service, method := splitFullMethod(fullMethod)
+	s.metrics.shadowRouted.WithLabelValues(service, method, variant).Inc()
+}
+
+func (s *shadow) countRequest(fullMethod, result string) {
+	if s.metrics == nil {
+		return
+	}
+	service, method := splitFullMethod(fullMethod)
+	s.metrics.shadowRequests.WithLabelValues(service, method, result).Inc()
+}
+
+// observeLabels records whether the top labels of the primary and the
+// candidate, sorted by score, match.
+func (s *shadow) observeLabels(fullMethod string, primary, candidate []string) {
+	if s.metrics == nil || len(primary) == 0 && len(candidate) == 0 {
+		return
+	}
+	result := "mismatch"
+	if len(primary) > 0 && len(candidate) > 0 && primary[0] == candidate[0] {
+		result = "match"
+	}
+	service, method := splitFullMethod(fullMethod)
+	s.metrics.shadowLabels.WithLabelValues(service, method, result).Inc()
+}
+
+// observeEntities records the Jaccard similarity of the entities found by the
+// primary and the candidate.
+func (s *shadow) observeEntities(fullMethod string, primary, candidate []*tokenclassificationv1.Token) {
+	if s.metrics == nil {
+		return
+	}
+	service, method := splitFullMethod(fullMethod)
+	s.metrics.shadowJaccard.WithLabelValues(service, method).Observe(entityJaccard(primary, candidate))
+}
+
+// observeEmbeddings records the cosine similarity of the embeddings of the
+// primary and the candidate.
+func (s *shadow) observeEmbeddings(fullMethod string, primary, candidate []float32) {
+	if s.metrics == nil {
+		return
+	}
+	service, method := splitFullMethod(fullMethod)
+	s.metrics.shadowCosine.WithLabelValues(service, method).Observe(cosineSimilarity(primary, candidate))
+}
+
+// entity is a labeled span of the input, as found by token classification.
+type entity struct {
+	start, end int32
+	label      string
+}
+
+// entityJaccard returns the Jaccard similarity of two sets of entities, i.e.
+// the number of entities found by both over the number of entities found by
+// either, 1 if none is found.
+func entityJaccard(a, b []*tokenclassificationv1.Token) float64 {
+	entities := make(map[entity]int, len(a))
+	for _, t := range a {
+		entities[entity{start: t.GetStart(), end: t.GetEnd(), label: t.GetLabel()}] |= 1
+	}
+	for _, t := range b {
+		entities[entity{start: t.GetStart(), end: t.GetEnd(), label: t.GetLabel()}] |= 2
+	}
+	if len(entities) == 0 {
+		return 1
+	}
+	both := 0
+	for _, found := range entities {
+		if found == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(entities))
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, 0 if their
+// lengths differ or if either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// shadowServerForTextClassification is the shadow deployment of a text
+// classification model.
+type shadowServerForTextClassification struct {
+	*serverForTextClassification
+	candidate *serverForTextClassification
+	*shadow
+}
+
+func (s *shadowServerForTextClassification) RegisterServer(r grpc.ServiceRegistrar) error {
+	textclassificationv1.RegisterTextClassificationServiceServer(r, s)
+	return nil
+}
+
+func (s *shadowServerForTextClassification) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	return textclassificationv1.RegisterTextClassificationServiceHandlerServer(ctx, mux, s)
+}
+
+// Classify handles the Classify request.
+func (s *shadowServerForTextClassification) Classify(ctx context.Context, req *textclassificationv1.ClassifyRequest) (*textclassificationv1.ClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, textclassificationv1.TextClassificationService_Classify_FullMethodName, req,
+		s.serverForTextClassification.Classify, s.candidate.Classify,
+		func(s *shadow, method string, primary, candidate *textclassificationv1.ClassifyResponse) {
+			s.observeLabels(method, primary.GetLabels(), candidate.GetLabels())
+		})
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *shadowServerForTextClassification) BatchClassify(ctx context.Context, req *textclassificationv1.BatchClassifyRequest) (*textclassificationv1.BatchClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, textclassificationv1.TextClassificationService_BatchClassify_FullMethodName, req,
+		s.serverForTextClassification.BatchClassify, s.candidate.BatchClassify,
+		func(s *shadow, method string, primary, candidate *textclassificationv1.BatchClassifyResponse) {
+			for i, n := 0, min(len(primary.GetResults()), len(candidate.GetResults())); i < n; i++ {
+				p, c := primary.GetResults()[i].GetResponse(), candidate.GetResults()[i].GetResponse()
+				if p != nil && c != nil {
+					s.observeLabels(method, p.GetLabels(), c.GetLabels())
+				}
+			}
+		})
+}
+
+// shadowServerForZeroShotClassification is the shadow deployment of a
+// zero-shot classification model.
+type shadowServerForZeroShotClassification struct {
+	*serverForZeroShotClassification
+	candidate *serverForZeroShotClassification
+	*shadow
+}
+
+func (s *shadowServerForZeroShotClassification) RegisterServer(r grpc.ServiceRegistrar) error {
+	zeroshotv1.RegisterZeroShotServiceServer(r, s)
+	return nil
+}
+
+func (s *shadowServerForZeroShotClassification) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	return zeroshotv1.RegisterZeroShotServiceHandlerServer(ctx, mux, s)
+}
+
+// Classify handles the Classify request.
+func (s *shadowServerForZeroShotClassification) Classify(ctx context.Context, req *zeroshotv1.ClassifyRequest) (*zeroshotv1.ClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, zeroshotv1.ZeroShotService_Classify_FullMethodName, req,
+		s.serverForZeroShotClassification.Classify, s.candidate.Classify,
+		func(s *shadow, method string, primary, candidate *zeroshotv1.ClassifyResponse) {
+			s.observeLabels(method, primary.GetLabels(), candidate.GetLabels())
+		})
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *shadowServerForZeroShotClassification) BatchClassify(ctx context.Context, req *zeroshotv1.BatchClassifyRequest) (*zeroshotv1.BatchClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, zeroshotv1.ZeroShotService_BatchClassify_FullMethodName, req,
+		s.serverForZeroShotClassification.BatchClassify, s.candidate.BatchClassify,
+		func(s *shadow, method string, primary, candidate *zeroshotv1.BatchClassifyResponse) {
+			for i, n := 0, min(len(primary.GetResults()), len(candidate.GetResults())); i < n; i++ {
+				p, c := primary.GetResults()[i].GetResponse(), candidate.GetResults()[i].GetResponse()
+				if p != nil && c != nil {
+					s.observeLabels(method, p.GetLabels(), c.GetLabels())
+				}
+			}
+		})
+}
+
+// shadowServerForTokenClassification is the shadow deployment of a token
+// classification model.
+type shadowServerForTokenClassification struct {
+	*serverForTokenClassification
+	candidate *serverForTokenClassification
+	*shadow
+}
+
+func (s *shadowServerForTokenClassification) RegisterServer(r grpc.ServiceRegistrar) error {
+	tokenclassificationv1.RegisterTokenClassificationServiceServer(r, s)
+	return nil
+}
+
+func (s *shadowServerForTokenClassification) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	return tokenclassificationv1.RegisterTokenClassificationServiceHandlerServer(ctx, mux, s)
+}
+
+// Classify handles the Classify request.
+func (s *shadowServerForTokenClassification) Classify(ctx context.Context, req *tokenclassificationv1.ClassifyRequest) (*tokenclassificationv1.ClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, tokenclassificationv1.TokenClassificationService_Classify_FullMethodName, req,
+		s.serverForTokenClassification.Classify, s.candidate.Classify,
+		func(s *shadow, method string, primary, candidate *tokenclassificationv1.ClassifyResponse) {
+			s.observeEntities(method, primary.GetTokens(), candidate.GetTokens())
+		})
+}
+
+// BatchClassify handles the BatchClassify request.
+func (s *shadowServerForTokenClassification) BatchClassify(ctx context.Context, req *tokenclassificationv1.BatchClassifyRequest) (*tokenclassificationv1.BatchClassifyResponse, error) {
+	return shadowCall(ctx, s.shadow, tokenclassificationv1.TokenClassificationService_BatchClassify_FullMethodName, req,
+		s.serverForTokenClassification.BatchClassify, s.candidate.BatchClassify,
+		func(s *shadow, method string, primary, candidate *tokenclassificationv1.BatchClassifyResponse) {
+			for i, n := 0, min(len(primary.GetResults()), len(candidate.GetResults())); i < n; i++ {
+				p, c := primary.GetResults()[i].GetResponse(), candidate.GetResults()[i].GetResponse()
+				if p != nil && c != nil {
+					s.observeEntities(method, p.GetTokens(), c.GetTokens())
+				}
+			}
+		})
+}
+
+// shadowServerForTextEncoding is the shadow deployment of a text encoding
+// model.
+type shadowServerForTextEncoding struct {
+	*serverForTextEncoding
+	candidate *serverForTextEncoding
+	*shadow
+}
+
+func (s *shadowServerForTextEncoding) RegisterServer(r grpc.ServiceRegistrar) error {
+	textencodingv1.RegisterTextEncodingServiceServer(r, s)
+	return nil
+}
+
+func (s *shadowServerForTextEncoding) RegisterHandlerServer(ctx context.Context, mux *runtime.ServeMux) error {
+	return textencodingv1.RegisterTextEncodingServiceHandlerServer(ctx, mux, s)
+}
+
+// Encode handles the Encode request.
+func (s *shadowServerForTextEncoding) Encode(ctx context.Context, req *textencodingv1.EncodingRequest) (*textencodingv1.EncodingResponse, error) {
+	return shadowCall(ctx, s.shadow, textencodingv1.TextEncodingService_Encode_FullMethodName, req,
+		s.serverForTextEncoding.Encode, s.candidate.Encode,
+		func(s *shadow, method string, primary, candidate *textencodingv1.EncodingResponse) {
+			s.observeEmbeddings(method, primary.GetVector(), candidate.GetVector())
+		})
+}
+
+// BatchEncode handles the BatchEncode request.
+func (s *shadowServerForTextEncoding) BatchEncode(ctx context.Context, req *textencodingv1.BatchEncodingRequest) (*textencodingv1.BatchEncodingResponse, error) {
+	return shadowCall(ctx, s.shadow, textencodingv1.TextEncodingService_BatchEncode_FullMethodName, req,
+		s.serverForTextEncoding.BatchEncode, s.candidate.BatchEncode,
+		func(s *shadow, method string, primary, candidate *textencodingv1.BatchEncodingResponse) {
+			for i, n := 0, min(len(primary.GetResults()), len(candidate.GetResults())); i < n; i++ {
+				p, c := primary.GetResults()[i].GetResponse(), candidate.GetResults()[i].GetResponse()
+				if p != nil && c != nil {
+					s.observeEmbeddings(method, p.GetVector(), c.GetVector())
+				}
+			}
+		})
+}
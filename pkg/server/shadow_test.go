@@ -0,0 +1,112 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tokenclassificationv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/tokenclassification/v1"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+)
+
+func TestNewShadowServer(t *testing.T) {
+	_, err := NewShadowServer(NewServerForTextClassification(fakeClassifier{}), NewServerForTextEncoding(fakeEncoder{}), ShadowConfig{})
+	assert.Error(t, err, "different tasks")
+	_, err = NewShadowServer(NewRegistry(), NewRegistry(), ShadowConfig{})
+	assert.Error(t, err, "unsupported handler")
+	_, err = NewShadowServer(NewServerForTextClassification(fakeClassifier{}), NewServerForTextClassification(fakeClassifier{}), ShadowConfig{CandidateWeight: 2})
+	assert.Error(t, err, "invalid weight")
+}
+
+func TestEntityJaccard(t *testing.T) {
+	token := func(start, end int32, label string) *tokenclassificationv1.Token {
+		return &tokenclassificationv1.Token{Start: start, End: end, Label: label}
+	}
+	assert.Equal(t, 1.0, entityJaccard(nil, nil))
+	assert.Equal(t, 1.0, entityJaccard([]*tokenclassificationv1.Token{token(0, 5, "PER")}, []*tokenclassificationv1.Token{token(0, 5, "PER")}))
+	assert.Equal(t, 1.0/3, entityJaccard(
+		[]*tokenclassificationv1.Token{token(0, 5, "PER"), token(10, 15, "LOC")},
+		[]*tokenclassificationv1.Token{token(0, 5, "PER"), token(10, 15, "ORG")},
+	))
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, cosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0, cosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{1, 0}, []float32{1}), "different lengths")
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{0, 0}, []float32{1, 1}), "zero vector")
+}
+
+func TestShadowContext(t *testing.T) {
+	conf := &Config{MaxInputTokens: 128, RequestTimeout: time.Minute}
+	ctx, cancel := limitedRequestContext(context.Background(), newAdmission(conf), conf)
+	shadowCtx, cancelShadow := shadowContext(ctx)
+	defer cancelShadow()
+	cancel()
+
+	assert.NoError(t, shadowCtx.Err(), "not canceled with the primary")
+	assert.Equal(t, 128, inputlimit.MaxTokens(shadowCtx, 512))
+	_, ok := shadowCtx.Deadline()
+	assert.True(t, ok)
+	priority, err := priorityFromContext(shadowCtx)
+	require.NoError(t, err)
+	assert.Equal(t, PriorityBatch, priority)
+}
+
+func TestServer_Shadow(t *testing.T) {
+	classify := func(t *testing.T, s *Server) []string {
+		resp, err := http.Post("http://"+s.ClientAddr()+"/v1/classify", "application/json", strings.NewReader(`{"input": "great"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct{ Labels []string }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Labels
+	}
+
+	t.Run("shadow", func(t *testing.T) {
+		h, err := NewShadowServer(
+			NewServerForTextClassification(fakeClassifier{label: "positive"}),
+			NewServerForTextClassification(fakeClassifier{label: "negative"}),
+			ShadowConfig{},
+		)
+		require.NoError(t, err)
+		s := startServer(t, &Config{MetricsEnabled: true}, h)
+
+		assert.Equal(t, []string{"positive"}, classify(t, s), "served by the primary")
+		assert.Eventually(t, func() bool {
+			resp, err := http.Get("http://" + s.ClientAddr() + "/metrics")
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return false
+			}
+			return strings.Contains(string(body), `cybertron_shadow_label_comparisons_total{method="Classify",result="mismatch",service="textclassification.v1.TextClassificationService"} 1`)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("A/B", func(t *testing.T) {
+		h, err := NewShadowServer(
+			NewServerForTextClassification(fakeClassifier{label: "positive"}),
+			NewServerForTextClassification(fakeClassifier{label: "negative"}),
+			ShadowConfig{CandidateWeight: 1},
+		)
+		require.NoError(t, err)
+		s := startServer(t, &Config{}, h)
+
+		assert.Equal(t, []string{"negative"}, classify(t, s), "served by the candidate")
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// certReloadInterval is the minimum interval between two checks for rotated
+// certificate files.
+const certReloadInterval = 10 * time.Second
+
+// certReloader serves the TLS certificate of the server, and the CA bundle
+// verifying the client certificates, reloading the files when they change.
+//
+// The files are checked during the TLS handshakes, at most once every
+// certReloadInterval, so that rotated certificates are picked up by the new
+// connections without restarting the server.
+type certReloader struct {
+	certFile, keyFile, caFile string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  [3]time.Time
+	checked   time.Time
+}
+
+func newCertReloader(certFile, keyFile, caFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads the files. The caller must hold mu, except at construction.
+func (r *certReloader) load() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS public/private key pair: %w", err)
+	}
+	var clientCAs *x509.CertPool
+	if r.caFile != "" {
+		if clientCAs, err = loadCertPool(r.caFile); err != nil {
+			return err
+		}
+	}
+	r.cert, r.clientCAs, r.modTimes, r.checked = &cert, clientCAs, modTimes, time.Now()
+	return nil
+}
+
+// stat returns the modification times of the files.
+func (r *certReloader) stat() (modTimes [3]time.Time, _ error) {
+	for i, name := range []string{r.certFile, r.keyFile, r.caFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, fmt.Errorf("failed to stat TLS file: %w", err)
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// current returns the certificate and the client CAs, reloading them first
+// if the files changed. If the new files cannot be loaded, for example because
+// they are being written, the previous ones are kept.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < certReloadInterval {
+		return r.cert, r.clientCAs
+	}
+	r.checked = time.Now()
+	if modTimes, err := r.stat(); err != nil || modTimes == r.modTimes {
+		return r.cert, r.clientCAs
+	}
+	if err := r.load(); err != nil {
+		log.Warn().Err(err).Msg("failed to reload TLS certificates, keeping the previous ones")
+		return r.cert, r.clientCAs
+	}
+	log.Info().Msg("TLS certificates reloaded")
+	return r.cert, r.clientCAs
+}
+
+// tlsConfig returns the TLS configuration of the server, requiring and
+// verifying the client certificates if a CA bundle is configured.
+func (r *certReloader) tlsConfig() *tls.Config {
+	newConfig := func() *tls.Config {
+		cert, clientCAs := r.current()
+		conf := &tls.Config{
+			Certificates: []tls.Certificate{*cert},
+			NextProtos:   []string{"h2"},
+			MinVersion:   tls.VersionTLS12,
+		}
+		if clientCAs != nil {
+			conf.ClientCAs = clientCAs
+			conf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		return conf
+	}
+	conf := newConfig()
+	conf.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return newConfig(), nil
+	}
+	return conf
+}
+
+// loadCertPool reads a bundle of PEM certificates.
+func loadCertPool(filename string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid certificates in CA bundle %q", filename)
+	}
+	return pool, nil
+}
+
+// subjectAuthorizer allows the clients whose certificate matches one of the
+// allowed subjects: the common name, the full distinguished name, or one of
+// the DNS, URI or email alternative names.
+type subjectAuthorizer map[string]struct{}
+
+func newSubjectAuthorizer(subjects []string) subjectAuthorizer {
+	a := make(subjectAuthorizer, len(subjects))
+	for _, s := range subjects {
+		a[s] = struct{}{}
+	}
+	return a
+}
+
+// authorize returns a PermissionDenied error if the client certificate, the
+// first of the verified chain, is missing or does not match any subject.
+func (a subjectAuthorizer) authorize(state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return status.Error(codes.PermissionDenied, "missing client certificate")
+	}
+	cert := state.PeerCertificates[0]
+	names := []string{cert.Subject.CommonName, cert.Subject.String()}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		if _, ok := a[name]; ok && name != "" {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "client certificate subject %q is not allowed", cert.Subject.String())
+}
+
+// authorizePeer authorizes the client of a gRPC call.
+func (a subjectAuthorizer) authorizePeer(ctx context.Context) error {
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+	return a.authorize(state)
+}
+
+// subjectsUnaryServerInterceptor returns a gRPC interceptor authorizing the
+// unary calls by client certificate.
+func subjectsUnaryServerInterceptor(a subjectAuthorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isInfrastructureMethod(info.FullMethod) {
+			if err := a.authorizePeer(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// subjectsStreamServerInterceptor returns a gRPC interceptor authorizing the
+// streaming calls by client certificate.
+func subjectsStreamServerInterceptor(a subjectAuthorizer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isInfrastructureMethod(info.FullMethod) {
+			if err := a.authorizePeer(ss.Context()); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// subjectsMiddleware returns an HTTP handler authorizing the requests served
+// by the gateway by client certificate, except for the metrics and the
+// probes.
+func subjectsMiddleware(a subjectAuthorizer, mux *runtime.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isInfrastructurePath(r.URL.Path) {
+			if err := a.authorize(r.TLS); err != nil {
+				writeHTTPError(mux, w, r, err)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testCA issues certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, ca.path("ca.pem"), "CERTIFICATE", der)
+	return ca
+}
+
+func (ca *testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue writes the certificate and key files of the given subject, returning
+// their paths.
+func (ca *testCA) issue(t *testing.T, name string, serial int64) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = ca.path(name+".pem"), ca.path(name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, filename, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func TestCertReloader(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", 2)
+
+	r, err := newCertReloader(certFile, keyFile, ca.path("ca.pem"))
+	require.NoError(t, err)
+	serial := func() int64 {
+		cert, _ := r.current()
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(2), serial())
+
+	ca.issue(t, "server", 3)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	assert.Equal(t, int64(2), serial(), "the files are not checked again before certReloadInterval")
+
+	r.checked = time.Time{}
+	assert.Equal(t, int64(3), serial(), "the rotated certificate is loaded")
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("partially written"), 0o600))
+	r.checked = time.Time{}
+	assert.Equal(t, int64(3), serial(), "invalid files are ignored")
+}
+
+func TestSubjectAuthorizer(t *testing.T) {
+	a := newSubjectAuthorizer([]string{"allowed", "spiffe://example.org/ns/test"})
+	state := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+
+	assert.NoError(t, a.authorize(state(&x509.Certificate{Subject: pkix.Name{CommonName: "allowed"}})))
+	spiffeID, err := url.Parse("spiffe://example.org/ns/test")
+	require.NoError(t, err)
+	assert.NoError(t, a.authorize(state(&x509.Certificate{URIs: []*url.URL{spiffeID}})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(a.authorize(state(&x509.Certificate{Subject: pkix.Name{CommonName: "other"}}))))
+	assert.Equal(t, codes.PermissionDenied, status.Code(a.authorize(nil)))
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", 2)
+	s := startServer(t, &Config{
+		TLSEnabled:        true,
+		TLSCert:           certFile,
+		TLSKey:            keyFile,
+		TLSClientCA:       ca.path("ca.pem"),
+		TLSClientSubjects: []string{"allowed"},
+	}, NewServerForTextEncoding(instrumentedEncoder{}))
+
+	encode := func(subject string) error {
+		certFile, keyFile := ca.issue(t, subject, 4)
+		c := client.NewClientForTextEncoding(s.ClientAddr(), client.Options{
+			UseTLS:         true,
+			CertFile:       ca.path("ca.pem"),
+			ClientCertFile: certFile,
+			ClientKeyFile:  keyFile,
+		})
+		_, err := c.Encode(context.Background(), "a", 0)
+		return err
+	}
+	assert.NoError(t, encode("allowed"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(encode("other")))
+}
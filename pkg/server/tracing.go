@@ -0,0 +1,104 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TracingExporterOTLP exports the spans to an OTLP collector over gRPC.
+	TracingExporterOTLP = "otlp"
+	// TracingExporterStdout writes the spans to the standard output.
+	TracingExporterStdout = "stdout"
+)
+
+// tracingPropagator extracts the trace context of the incoming requests.
+var tracingPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// NewTracerProvider returns a TracerProvider exporting the spans with the
+// given exporter (TracingExporterOTLP or TracingExporterStdout).
+//
+// The OTLP exporter sends the spans to the endpoint ("host:port"), which
+// defaults to the OTEL_EXPORTER_OTLP_ENDPOINT environment variable, or to
+// localhost:4317. The provider must be shut down to flush the pending spans.
+func NewTracerProvider(ctx context.Context, exporter, endpoint string) (*sdktrace.TracerProvider, error) {
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	switch exporter {
+	case TracingExporterOTLP:
+		var opts []otlptracegrpc.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+		}
+		exp, err = otlptracegrpc.New(ctx, opts...)
+	case TracingExporterStdout:
+		exp, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tracing exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", "cybertron")))
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res)), nil
+}
+
+// tracingServerOption returns the gRPC server option creating a span for
+// each call, continuing the incoming trace context.
+func tracingServerOption(tp trace.TracerProvider) grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPropagators(tracingPropagator),
+	))
+}
+
+// tracingMiddleware returns an HTTP handler creating a span for each request
+// served by the gateway, continuing the incoming trace context.
+func tracingMiddleware(tp trace.TracerProvider, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithTracerProvider(tp),
+		otelhttp.WithPropagators(tracingPropagator),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return !isInfrastructurePath(r.URL.Path)
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// tracingServeMuxOption returns the option of the gateway mux naming the
+// span of each request after its RPC method, as for gRPC calls.
+func tracingServeMuxOption() runtime.ServeMuxOption {
+	return runtime.WithMetadata(func(ctx context.Context, _ *http.Request) metadata.MD {
+		if method, ok := runtime.RPCMethod(ctx); ok {
+			trace.SpanFromContext(ctx).SetName(strings.TrimPrefix(method, "/"))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	textencodingv1 "github.com/yinziyang/cybertron/pkg/server/gen/proto/go/textencoding/v1"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestServer_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	r := NewRegistry()
+	require.NoError(t, r.Add("embeddings", instrumentedEncoder{}))
+	s := startServer(t, &Config{TracerProvider: tp}, r)
+
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	t.Run("grpc", func(t *testing.T) {
+		exporter.Reset()
+		conn, err := grpc.Dial(s.ClientAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", traceparent)
+		_, err = textencodingv1.NewTextEncodingServiceClient(conn).Encode(ctx, &textencodingv1.EncodingRequest{Input: "a b"})
+		require.NoError(t, err)
+
+		assertRequestSpans(t, exporter.GetSpans(), traceID)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		exporter.Reset()
+		req, err := http.NewRequest(http.MethodPost, "http://"+s.ClientAddr()+"/v1/encode", strings.NewReader(`{"input": "a b"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("traceparent", traceparent)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assertRequestSpans(t, exporter.GetSpans(), traceID)
+	})
+}
+
+// assertRequestSpans asserts that the spans of an Encode request continue
+// the incoming trace, and that the forward stage is a child of the request.
+func assertRequestSpans(t *testing.T, spans tracetest.SpanStubs, traceID string) {
+	t.Helper()
+	require.Len(t, spans, 2)
+	forward, request := spans[0], spans[1]
+
+	assert.Equal(t, "textencoding.v1.TextEncodingService/Encode", request.Name)
+	assert.Equal(t, traceID, request.SpanContext.TraceID().String())
+	assert.Contains(t, request.Attributes, instrument.ModelKey.String("embeddings"))
+
+	assert.Equal(t, "text-encoding/forward", forward.Name)
+	assert.Equal(t, request.SpanContext.SpanID(), forward.Parent.SpanID())
+	assert.Equal(t, trace.SpanKindInternal, forward.SpanKind)
+}
@@ -84,6 +84,32 @@ var floatPrecisionValues = map[string]FloatPrecision{
 	"64": F64,
 }
 
+// valueName returns the name of the given value in the list of supported
+// values.
+func valueName[T ~int](values map[string]T, v T) string {
+	for name, value := range values {
+		if value == v {
+			return name
+		}
+	}
+	return fmt.Sprintf("%T(%d)", v, int(v))
+}
+
+// String returns the name of the download policy, as parsed by ParseDownloadPolicy.
+func (p DownloadPolicy) String() string {
+	return valueName(downloadPolicyValues, p)
+}
+
+// String returns the name of the conversion policy, as parsed by ParseConversionPolicy.
+func (p ConversionPolicy) String() string {
+	return valueName(conversionPolicyValues, p)
+}
+
+// String returns the name of the precision, as parsed by ParseFloatPrecision.
+func (p FloatPrecision) String() string {
+	return valueName(floatPrecisionValues, p)
+}
+
 // ParseDownloadPolicy parses a string into a download policy.
 func ParseDownloadPolicy(s string) (DownloadPolicy, error) {
 	result, ok := downloadPolicyValues[s]
@@ -0,0 +1,29 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package inputlimit lets the callers of the tasks lower the maximum length
+// of the input sequences below the limit of the model, through the context.
+package inputlimit
+
+import "context"
+
+type maxTokensKey struct{}
+
+// WithMaxTokens returns a copy of the context limiting the number of tokens
+// of the input sequences to max. A max <= 0 means no limit.
+func WithMaxTokens(ctx context.Context, max int) context.Context {
+	if max <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, maxTokensKey{}, max)
+}
+
+// MaxTokens returns the maximum number of tokens of the input sequences: the
+// lowest of the limit of the model and the one set in the context, if any.
+func MaxTokens(ctx context.Context, modelMax int) int {
+	if max, ok := ctx.Value(maxTokensKey{}).(int); ok && max < modelMax {
+		return max
+	}
+	return modelMax
+}
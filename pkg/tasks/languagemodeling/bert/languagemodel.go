@@ -12,17 +12,23 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
 	"github.com/yinziyang/cybertron/pkg/tasks/languagemodeling"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tokenizers"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
 	"github.com/yinziyang/cybertron/pkg/vocabulary"
-	"github.com/nlpodyssey/spago/mat"
-	"github.com/nlpodyssey/spago/nn"
 )
 
 const defaultTopK = 10
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "language-modeling"
+
 // LanguageModel is a masked language model.
 type LanguageModel struct {
 	// Model is the model used to answer questions.
@@ -62,17 +68,22 @@ func LoadMaskedLanguageModel(modelPath string) (*LanguageModel, error) {
 }
 
 // Predict returns the predicted tokens
-func (m *LanguageModel) Predict(_ context.Context, text string, parameters languagemodeling.Parameters) (languagemodeling.Response, error) {
+func (m *LanguageModel) Predict(ctx context.Context, text string, parameters languagemodeling.Parameters) (languagemodeling.Response, error) {
 	if parameters.K == 0 {
 		parameters.K = defaultTopK
 	}
 
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	tokenized := pad(m.tokenize(text))
-	if l, k := len(tokenized), m.Model.Bert.Config.MaxPositionEmbeddings; l > k {
-		return languagemodeling.Response{}, fmt.Errorf("%w: %d > %d", languagemodeling.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(tokenized)))
+	instrument.InputTokens(ctx, taskName, len(tokenized))
+	if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+		return languagemodeling.Response{}, taskerrors.InputTooLong(languagemodeling.ErrInputSequenceTooLong, l, k)
 	}
 
+	_, span = instrument.Start(ctx, taskName, instrument.Forward)
 	prediction := m.Model.Predict(tokenizers.GetStrings(tokenized))
+	span.End()
 
 	result := make([]languagemodeling.Token, 0, len(prediction))
 	for i, logits := range prediction {
@@ -12,15 +12,18 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
 	"github.com/yinziyang/cybertron/pkg/tasks/questionanswering"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tokenizers"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
 	"github.com/yinziyang/cybertron/pkg/utils/sliceutils"
 	"github.com/yinziyang/cybertron/pkg/vocabulary"
-	"github.com/nlpodyssey/spago/ag"
-	"github.com/nlpodyssey/spago/mat"
-	"github.com/nlpodyssey/spago/nn"
 )
 
 const (
@@ -30,6 +33,9 @@ const (
 	defaultMaxAnswers      = 3
 )
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "question-answering"
+
 // QuestionAnswering is a QuestionAnswering model.
 type QuestionAnswering struct {
 	// Model is the model used to answer questions.
@@ -59,15 +65,59 @@ func LoadQuestionAnswering(modelPath string) (*QuestionAnswering, error) {
 
 // ExtractAnswer returns the answers for the given question and passage.
 // The options may assume default values if those are not set.
-func (qa *QuestionAnswering) ExtractAnswer(_ context.Context, question string, passage string, opts *questionanswering.Options) (questionanswering.Response, error) {
+func (qa *QuestionAnswering) ExtractAnswer(ctx context.Context, question string, passage string, opts *questionanswering.Options) (questionanswering.Response, error) {
 	checkOptions(opts)
 
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	qt, pt := qa.tokenize(question, passage)
-	if l, k := len(qt)+len(pt), qa.Model.Bert.Config.MaxPositionEmbeddings; l > k {
-		return questionanswering.Response{}, fmt.Errorf("%w: %d > %d", questionanswering.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(qt) + len(pt)))
+	instrument.InputTokens(ctx, taskName, len(qt)+len(pt))
+	if l, k := len(qt)+len(pt), inputlimit.MaxTokens(ctx, qa.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+		return questionanswering.Response{}, taskerrors.InputTooLong(questionanswering.ErrInputSequenceTooLong, l, k)
 	}
 
+	_, span = instrument.Start(ctx, taskName, instrument.Forward)
 	starts, ends := qa.Model.Answer(concat(qt, pt))
+	span.End()
+
+	return answerResponse(starts, ends, qt, pt, passage, opts), nil
+}
+
+// ExtractAnswerBatch returns the answers for each of the given questions and passages.
+// The inputs are padded to the same length and answered in a single forward pass.
+// The options may assume default values if those are not set.
+func (qa *QuestionAnswering) ExtractAnswerBatch(ctx context.Context, inputs []questionanswering.Input, opts *questionanswering.Options) ([]questionanswering.Response, error) {
+	checkOptions(opts)
+
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization, instrument.BatchSizeKey.Int(len(inputs)))
+	questions := make([][]tokenizers.StringOffsetsPair, len(inputs))
+	passages := make([][]tokenizers.StringOffsetsPair, len(inputs))
+	batch := make([][]string, len(inputs))
+	for i, input := range inputs {
+		qt, pt := qa.tokenize(input.Question, input.Passage)
+		instrument.BatchInputTokens(ctx, taskName, i, len(qt)+len(pt))
+		if l, k := len(qt)+len(pt), inputlimit.MaxTokens(ctx, qa.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+			span.End()
+			return nil, taskerrors.InputTooLongAt(questionanswering.ErrInputSequenceTooLong, i, l, k)
+		}
+		questions[i], passages[i], batch[i] = qt, pt, concat(qt, pt)
+	}
+	span.End()
+
+	_, span = instrument.Start(ctx, taskName, instrument.Forward, instrument.BatchSizeKey.Int(len(inputs)))
+	starts, ends := qa.Model.AnswerBatch(batch)
+	span.End()
+
+	responses := make([]questionanswering.Response, len(inputs))
+	for i, input := range inputs {
+		responses[i] = answerResponse(starts[i], ends[i], questions[i], passages[i], input.Passage, opts)
+	}
+	return responses, nil
+}
+
+// answerResponse returns the most likely answers in the passage given the
+// "span start logits" and "span end logits" of the question and passage tokens.
+func answerResponse(starts, ends []mat.Tensor, qt, pt []tokenizers.StringOffsetsPair, passage string, opts *questionanswering.Options) questionanswering.Response {
 	starts, ends = adjustLogitsForInference(starts, ends, qt, pt)
 	startsIdx := getBestIndices(extractScores(starts), opts.MaxCandidates)
 	endsIdx := getBestIndices(extractScores(ends), opts.MaxCandidates)
@@ -75,7 +125,7 @@ func (qa *QuestionAnswering) ExtractAnswer(_ context.Context, question string, p
 	answers := filterUnlikelyCandidates(candidates, opts.MinScore)
 
 	if len(answers) == 0 {
-		return questionanswering.Response{}, nil
+		return questionanswering.Response{}
 	}
 
 	sort.Slice(answers, func(i, j int) bool {
@@ -88,7 +138,7 @@ func (qa *QuestionAnswering) ExtractAnswer(_ context.Context, question string, p
 
 	return questionanswering.Response{
 		Answers: answers,
-	}, nil
+	}
 }
 
 func checkOptions(opts *questionanswering.Options) {
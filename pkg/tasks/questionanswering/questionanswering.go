@@ -26,6 +26,17 @@ var ErrInputSequenceTooLong = errors.New("input sequence too long")
 // Interface defines the main functions for question-answering task.
 type Interface interface {
 	ExtractAnswer(ctx context.Context, question string, passage string, opts *Options) (Response, error)
+	// ExtractAnswerBatch returns the answers for each of the given inputs,
+	// in the same order as the inputs.
+	ExtractAnswerBatch(ctx context.Context, inputs []Input, opts *Options) ([]Response, error)
+}
+
+// Input is a question to answer from a passage.
+type Input struct {
+	// Question is the question to answer.
+	Question string
+	// Passage is the text containing the answer.
+	Passage string
 }
 
 // Options defines the options for question-answering task.
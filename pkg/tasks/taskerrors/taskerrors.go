@@ -0,0 +1,45 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package taskerrors provides the error types shared by the task
+// implementations, carrying the details needed to report the errors to
+// clients.
+package taskerrors
+
+import "fmt"
+
+// InputTooLongError reports an input sequence longer than the maximum length
+// allowed by the model. It wraps the ErrInputSequenceTooLong error of the task.
+type InputTooLongError struct {
+	// Err is the ErrInputSequenceTooLong error of the task.
+	Err error
+	// Index is the position of the input in a batch, or -1 for a single input.
+	Index int
+	// Length is the number of tokens of the input sequence.
+	Length int
+	// MaxLength is the maximum number of tokens allowed by the model.
+	MaxLength int
+}
+
+// InputTooLong returns an InputTooLongError for a single input sequence.
+func InputTooLong(err error, length, maxLength int) *InputTooLongError {
+	return &InputTooLongError{Err: err, Index: -1, Length: length, MaxLength: maxLength}
+}
+
+// InputTooLongAt returns an InputTooLongError for the input sequence at the
+// given index of a batch.
+func InputTooLongAt(err error, index, length, maxLength int) *InputTooLongError {
+	return &InputTooLongError{Err: err, Index: index, Length: length, MaxLength: maxLength}
+}
+
+func (e *InputTooLongError) Error() string {
+	if e.Index >= 0 {
+		return fmt.Sprintf("%v: text %d: %d > %d", e.Err, e.Index, e.Length, e.MaxLength)
+	}
+	return fmt.Sprintf("%v: %d > %d", e.Err, e.Length, e.MaxLength)
+}
+
+func (e *InputTooLongError) Unwrap() error {
+	return e.Err
+}
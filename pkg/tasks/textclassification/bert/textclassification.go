@@ -19,7 +19,10 @@ import (
 
 	"github.com/nlpodyssey/spago/nn"
 	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tasks/textclassification"
 	"github.com/yinziyang/cybertron/pkg/tokenizers"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
@@ -27,6 +30,9 @@ import (
 	"github.com/yinziyang/cybertron/pkg/vocabulary"
 )
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "text-classification"
+
 // TextClassification is a text classification model.
 type TextClassification struct {
 	// Model is the model used to answer questions.
@@ -87,19 +93,57 @@ func ID2Label(value map[string]string) []string {
 }
 
 // Classify returns the classification of the given text.
-func (m *TextClassification) Classify(_ context.Context, text string) (textclassification.Response, error) {
+func (m *TextClassification) Classify(ctx context.Context, text string) (textclassification.Response, error) {
 	origlog.Println("tokenize start")
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	tokenized := m.tokenize(text)
-	if l, k := len(tokenized), m.Model.Bert.Config.MaxPositionEmbeddings; l > k {
-		return textclassification.Response{}, fmt.Errorf("%w: %d > %d", textclassification.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(tokenized)))
+	instrument.InputTokens(ctx, taskName, len(tokenized))
+	if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+		return textclassification.Response{}, taskerrors.InputTooLong(textclassification.ErrInputSequenceTooLong, l, k)
 	}
 	origlog.Println("tokenize end")
 
 	origlog.Println("classify start")
+	_, span = instrument.Start(ctx, taskName, instrument.Forward)
 	logits := m.Model.Classify(tokenized)
-	probs := logits.Value().(mat.Matrix).Softmax()
+	span.End()
 	origlog.Println("classify end")
 
+	return m.response(logits), nil
+}
+
+// ClassifyBatch returns the classification of each given text.
+// The texts are padded to the same length and classified in a single forward pass.
+func (m *TextClassification) ClassifyBatch(ctx context.Context, texts []string) ([]textclassification.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization, instrument.BatchSizeKey.Int(len(texts)))
+	batch := make([][]string, len(texts))
+	for i, text := range texts {
+		tokenized := m.tokenize(text)
+		instrument.BatchInputTokens(ctx, taskName, i, len(tokenized))
+		if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+			span.End()
+			return nil, taskerrors.InputTooLongAt(textclassification.ErrInputSequenceTooLong, i, l, k)
+		}
+		batch[i] = tokenized
+	}
+	span.End()
+
+	_, span = instrument.Start(ctx, taskName, instrument.Forward, instrument.BatchSizeKey.Int(len(texts)))
+	logits := m.Model.ClassifyBatch(batch)
+	span.End()
+	responses := make([]textclassification.Response, len(logits))
+	for i, l := range logits {
+		responses[i] = m.response(l)
+	}
+	return responses, nil
+}
+
+// response converts the logits into a response with the labels sorted
+// in descending order by probability.
+func (m *TextClassification) response(logits mat.Tensor) textclassification.Response {
+	probs := logits.Value().(mat.Matrix).Softmax()
+
 	result := sliceutils.NewIndexedSlice[float64](probs.Data().F64())
 	sort.Stable(sort.Reverse(result))
 
@@ -108,11 +152,10 @@ func (m *TextClassification) Classify(_ context.Context, text string) (textclass
 		labels[i] = m.Labels[ii]
 	}
 
-	response := textclassification.Response{
+	return textclassification.Response{
 		Labels: labels,
 		Scores: result.Slice,
 	}
-	return response, nil
 }
 
 // tokenize returns the tokens of the given text (including padding tokens).
@@ -29,6 +29,9 @@ var ErrInputSequenceTooLong = errors.New("input sequence too long")
 type Interface interface {
 	// Classify returns the classification of the given example.
 	Classify(ctx context.Context, text string) (Response, error)
+	// ClassifyBatch returns the classification of each given example,
+	// in the same order as the input texts.
+	ClassifyBatch(ctx context.Context, texts []string) ([]Response, error)
 }
 
 // Response contains the response from text classification.
@@ -11,17 +11,23 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
 	"github.com/yinziyang/cybertron/pkg/tokenizers"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
 	"github.com/yinziyang/cybertron/pkg/vocabulary"
-	"github.com/nlpodyssey/spago/mat"
-	"github.com/nlpodyssey/spago/nn"
 )
 
 var _ textencoding.Interface = &TextEncoding{}
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "text-encoding"
+
 // TextEncoding is a text encoding model.
 type TextEncoding struct {
 	// Model is the model used to answer questions.
@@ -58,12 +64,17 @@ func LoadTextEncoding(modelPath string) (*TextEncoding, error) {
 }
 
 // Encode returns the dense encoded representation of the given text.
-func (m *TextEncoding) Encode(_ context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+func (m *TextEncoding) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	tokenized := m.tokenize(text)
-	if l, k := len(tokenized), m.Model.Bert.Config.MaxPositionEmbeddings; l > k {
-		return textencoding.Response{}, fmt.Errorf("%w: %d > %d", textencoding.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(tokenized)))
+	instrument.InputTokens(ctx, taskName, len(tokenized))
+	if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+		return textencoding.Response{}, taskerrors.InputTooLong(textencoding.ErrInputSequenceTooLong, l, k)
 	}
+	_, span = instrument.Start(ctx, taskName, instrument.Forward)
 	encoded, err := m.Model.Encode(tokenized, bert.PoolingStrategyType(poolingStrategy))
+	span.End()
 	if err != nil {
 		return textencoding.Response{}, err
 	}
@@ -74,6 +85,38 @@ func (m *TextEncoding) Encode(_ context.Context, text string, poolingStrategy in
 	return response, nil
 }
 
+// EncodeBatch returns the dense encoded representation of each given text.
+// The texts are padded to the same length and encoded in a single forward pass.
+func (m *TextEncoding) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization, instrument.BatchSizeKey.Int(len(texts)))
+	batch := make([][]string, len(texts))
+	for i, text := range texts {
+		tokenized := m.tokenize(text)
+		instrument.BatchInputTokens(ctx, taskName, i, len(tokenized))
+		if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+			span.End()
+			return nil, taskerrors.InputTooLongAt(textencoding.ErrInputSequenceTooLong, i, l, k)
+		}
+		batch[i] = tokenized
+	}
+	span.End()
+
+	_, span = instrument.Start(ctx, taskName, instrument.Forward, instrument.BatchSizeKey.Int(len(texts)))
+	encoded, err := m.Model.EncodeBatch(batch, bert.PoolingStrategyType(poolingStrategy))
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]textencoding.Response, len(encoded))
+	for i, vector := range encoded {
+		responses[i] = textencoding.Response{
+			Vector: vector.Value().(mat.Matrix),
+		}
+	}
+	return responses, nil
+}
+
 // tokenize returns the tokens of the given text (including padding tokens).
 func (m *TextEncoding) tokenize(text string) []string {
 	if m.doLowerCase {
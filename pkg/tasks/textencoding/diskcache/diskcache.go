@@ -0,0 +1,366 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package diskcache provides a persistent cache of text embeddings, stored in
+// a local embedded key-value file, to avoid encoding the same texts again
+// across runs.
+//
+// The cache decorates any textencoding.Interface, such as the in-process
+// BERT encoder or the gRPC client. The embeddings are keyed by model name,
+// pooling strategy and hash of the text, and keep the data type they were
+// encoded with (float32 or float64).
+package diskcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+	"go.etcd.io/bbolt"
+)
+
+// FileName is the name of the file of the cache within its directory.
+const FileName = "embeddings.db"
+
+// Options are the options for opening a Store.
+type Options struct {
+	// ReadOnly opens the cache in read-only mode, so that several processes
+	// can share it. The encodings missing from a read-only cache are not
+	// stored.
+	ReadOnly bool
+	// LockTimeout is the maximum time to wait for another process to release
+	// the cache file. It waits indefinitely if zero.
+	LockTimeout time.Duration
+}
+
+// Store is a persistent cache of embeddings. It is safe for concurrent use:
+// the lookups run concurrently, while the writes are serialized.
+//
+// Only one process at a time can open a Store in read-write mode.
+type Store struct {
+	path string
+	opts Options
+
+	// mu guards db, which is replaced by Compact.
+	mu sync.RWMutex
+	db *bbolt.DB
+}
+
+// Open opens the cache stored in the given directory, creating it if needed.
+func Open(dir string, opts *Options) (*Store, error) {
+	s := &Store{path: filepath.Join(dir, FileName)}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if !s.opts.ReadOnly {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+	db, err := s.open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.db = db
+	return s, nil
+}
+
+func (s *Store) open(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0o644, &bbolt.Options{
+		Timeout:  s.opts.LockTimeout,
+		ReadOnly: s.opts.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embeddings cache %q: %w", path, err)
+	}
+	return db, nil
+}
+
+// Close closes the cache.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// Wrap returns a textencoding.Interface serving the embeddings of the
+// encoder from the cache, encoding and storing only the missing ones. The
+// name of the model identifies the embeddings of the encoder in the cache.
+func (s *Store) Wrap(model string, encoder textencoding.Interface) textencoding.Interface {
+	return &cachingEncoder{store: s, model: []byte(model), encoder: encoder}
+}
+
+// Compact removes the embeddings stored more than maxAge ago, if maxAge is
+// positive, and rewrites the cache file to reclaim the unused space. The
+// cache cannot be used while it is compacted.
+func (s *Store) Compact(maxAge time.Duration) error {
+	if s.opts.ReadOnly {
+		return errors.New("cannot compact a read-only cache")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if maxAge > 0 {
+		if err := s.removeOlderThan(time.Now().Add(-maxAge)); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := s.path + ".compact"
+	// a compaction interrupted by a crash may have left its file behind
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale compacted embeddings cache: %w", err)
+	}
+	dst, err := s.open(tmpPath)
+	if err != nil {
+		return err
+	}
+	err = bbolt.Compact(dst, s.db, 64<<20)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact embeddings cache: %w", err)
+	}
+	if err := s.db.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	// the original file is reopened if it cannot be replaced
+	replaceErr := os.Rename(tmpPath, s.path)
+	if replaceErr != nil {
+		_ = os.Remove(tmpPath)
+	}
+	db, err := s.open(s.path)
+	if err != nil {
+		return errors.Join(replaceErr, err)
+	}
+	s.db = db
+	if replaceErr != nil {
+		return fmt.Errorf("failed to replace embeddings cache: %w", replaceErr)
+	}
+	return nil
+}
+
+// removeOlderThan deletes the embeddings stored before t. The caller must
+// hold mu.
+func (s *Store) removeOlderThan(t time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(_ []byte, b *bbolt.Bucket) error {
+			var expired [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if storedAt(v).Before(t) {
+					expired = append(expired, k)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// get returns the vectors stored for the keys, nil if missing.
+func (s *Store) get(model []byte, keys [][]byte) ([]mat.Matrix, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vectors := make([]mat.Matrix, len(keys))
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(model)
+		if b == nil {
+			return nil
+		}
+		for i, k := range keys {
+			if v := b.Get(k); v != nil {
+				vectors[i] = decodeVector(v)
+			}
+		}
+		return nil
+	})
+	return vectors, err
+}
+
+// put stores the vectors for the keys.
+func (s *Store) put(model []byte, keys [][]byte, vectors []mat.Matrix) error {
+	if s.opts.ReadOnly {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	// concurrent writes are coalesced into a single transaction
+	return s.db.Batch(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(model)
+		if err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if err := b.Put(k, encodeVector(vectors[i], now)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// key returns the key of the text within the bucket of a model: the pooling
+// strategy followed by the hash of the text.
+func key(text string, poolingStrategy int) []byte {
+	hash := sha256.Sum256([]byte(text))
+	k := make([]byte, 4+len(hash))
+	binary.BigEndian.PutUint32(k, uint32(poolingStrategy))
+	copy(k[4:], hash[:])
+	return k
+}
+
+// encodeVector encodes the time of storage, the bit size of the values of
+// the vector (32 or 64), then the values, as little-endian values, so that
+// the vector is decoded with the data type it was encoded with.
+func encodeVector(vector mat.Matrix, t time.Time) []byte {
+	data := vector.Data()
+	bitSize := 32
+	if data.BitSize() == 64 {
+		bitSize = 64
+	}
+	v := make([]byte, 9+bitSize/8*data.Len())
+	binary.LittleEndian.PutUint64(v, uint64(t.Unix()))
+	v[8] = byte(bitSize)
+	if bitSize == 64 {
+		for i, x := range data.F64() {
+			binary.LittleEndian.PutUint64(v[9+8*i:], math.Float64bits(x))
+		}
+		return v
+	}
+	for i, x := range data.F32() {
+		binary.LittleEndian.PutUint32(v[9+4*i:], math.Float32bits(x))
+	}
+	return v
+}
+
+// decodeVector decodes a vector encoded by encodeVector. It returns nil if
+// the value is not a valid encoding, so that the vector is encoded again.
+func decodeVector(v []byte) mat.Matrix {
+	if len(v) < 9 {
+		return nil
+	}
+	switch bitSize, values := v[8], v[9:]; {
+	case bitSize == 32 && len(values)%4 == 0:
+		vector := make([]float32, len(values)/4)
+		for i := range vector {
+			vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(values[4*i:]))
+		}
+		return mat.NewDense[float32](mat.WithBacking(vector))
+	case bitSize == 64 && len(values)%8 == 0:
+		vector := make([]float64, len(values)/8)
+		for i := range vector {
+			vector[i] = math.Float64frombits(binary.LittleEndian.Uint64(values[8*i:]))
+		}
+		return mat.NewDense[float64](mat.WithBacking(vector))
+	default:
+		return nil
+	}
+}
+
+// storedAt returns the time of storage of a vector encoded by encodeVector.
+func storedAt(v []byte) time.Time {
+	return time.Unix(int64(binary.LittleEndian.Uint64(v)), 0)
+}
+
+// cachingEncoder is a textencoding.Interface backed by a Store.
+type cachingEncoder struct {
+	store   *Store
+	model   []byte
+	encoder textencoding.Interface
+}
+
+// Encode returns the cached embedding of the text, or encodes and stores it.
+func (e *cachingEncoder) Encode(ctx context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	responses, err := e.EncodeBatch(ctx, []string{text}, poolingStrategy)
+	if err != nil {
+		return textencoding.Response{}, err
+	}
+	return responses[0], nil
+}
+
+// EncodeBatch returns the cached embeddings of the texts, encoding and
+// storing the missing ones in a single batch.
+func (e *cachingEncoder) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	keys := make([][]byte, len(texts))
+	for i, text := range texts {
+		keys[i] = key(text, poolingStrategy)
+	}
+	vectors, err := e.store.get(e.model, keys)
+	if err != nil {
+		// the cache is an optimization: fall back to the encoder
+		log.Warn().Err(err).Msg("failed to read embeddings cache")
+		vectors = make([]mat.Matrix, len(texts))
+	}
+
+	var missing []int
+	for i, v := range vectors {
+		if v == nil {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) > 0 {
+		missingTexts := make([]string, len(missing))
+		for j, i := range missing {
+			missingTexts[j] = texts[i]
+		}
+		// the tokens of the missing texts are reported as those of the texts
+		encodeCtx := instrument.WithBatchInputs(ctx, missing)
+		var encoded []textencoding.Response
+		if len(missing) == 1 {
+			var r textencoding.Response
+			r, err = e.encoder.Encode(encodeCtx, missingTexts[0], poolingStrategy)
+			encoded = []textencoding.Response{r}
+		} else {
+			encoded, err = e.encoder.EncodeBatch(encodeCtx, missingTexts, poolingStrategy)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		newKeys := make([][]byte, len(missing))
+		newVectors := make([]mat.Matrix, len(missing))
+		for j, i := range missing {
+			vectors[i] = encoded[j].Vector
+			newKeys[j], newVectors[j] = keys[i], vectors[i]
+		}
+		if err := e.store.put(e.model, newKeys, newVectors); err != nil {
+			log.Warn().Err(err).Msg("failed to write embeddings cache")
+		}
+	}
+
+	responses := make([]textencoding.Response, len(texts))
+	for i, v := range vectors {
+		responses[i] = textencoding.Response{Vector: v}
+	}
+	return responses, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diskcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yinziyang/cybertron/pkg/tasks/textencoding"
+)
+
+// countingEncoder encodes a text as a vector of its length and pooling
+// strategy, counting the encoded texts.
+type countingEncoder struct {
+	encoded int
+}
+
+func (e *countingEncoder) Encode(_ context.Context, text string, poolingStrategy int) (textencoding.Response, error) {
+	e.encoded++
+	return textencoding.Response{Vector: mat.NewDense[float32](mat.WithBacking([]float32{float32(len(text)), float32(poolingStrategy)}))}, nil
+}
+
+func (e *countingEncoder) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	responses := make([]textencoding.Response, len(texts))
+	for i, text := range texts {
+		responses[i], _ = e.Encode(ctx, text, poolingStrategy)
+	}
+	return responses, nil
+}
+
+// float64Encoder encodes a text as a float64 vector of its length.
+type float64Encoder struct{}
+
+func (float64Encoder) Encode(_ context.Context, text string, _ int) (textencoding.Response, error) {
+	return textencoding.Response{Vector: mat.NewDense[float64](mat.WithBacking([]float64{float64(len(text)) + 0.1}))}, nil
+}
+
+func (e float64Encoder) EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]textencoding.Response, error) {
+	responses := make([]textencoding.Response, len(texts))
+	for i, text := range texts {
+		responses[i], _ = e.Encode(ctx, text, poolingStrategy)
+	}
+	return responses, nil
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	inner := &countingEncoder{}
+
+	s, err := Open(dir, nil)
+	require.NoError(t, err)
+	m := s.Wrap("model", inner)
+
+	r, err := m.Encode(ctx, "abc", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{3, 1}, r.Vector.Data().F32())
+	_, _ = m.Encode(ctx, "abc", 1)
+	assert.Equal(t, 1, inner.encoded)
+
+	_, _ = m.Encode(ctx, "abc", 2)
+	assert.Equal(t, 2, inner.encoded, "the pooling strategy is part of the key")
+	_, _ = s.Wrap("other", inner).Encode(ctx, "abc", 1)
+	assert.Equal(t, 3, inner.encoded, "the model name is part of the key")
+
+	rs, err := m.EncodeBatch(ctx, []string{"abc", "de", "f"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5, inner.encoded, "only the missing texts are encoded")
+	require.Len(t, rs, 3)
+	assert.Equal(t, []float32{2, 1}, rs[1].Vector.Data().F32())
+	assert.Equal(t, []float32{1, 1}, rs[2].Vector.Data().F32())
+	require.NoError(t, s.Close())
+
+	t.Run("persistence", func(t *testing.T) {
+		s, err := Open(dir, &Options{ReadOnly: true})
+		require.NoError(t, err)
+		defer s.Close()
+		inner := &countingEncoder{}
+		m := s.Wrap("model", inner)
+
+		rs, err := m.EncodeBatch(ctx, []string{"abc", "de", "new"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{2, 1}, rs[1].Vector.Data().F32())
+		assert.Equal(t, 1, inner.encoded)
+		_, _ = m.Encode(ctx, "new", 1)
+		assert.Equal(t, 2, inner.encoded, "read-only caches are not written")
+		assert.Error(t, s.Compact(0))
+	})
+}
+
+func TestStore_Compact(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEncoder{}
+	s, err := Open(t.TempDir(), nil)
+	require.NoError(t, err)
+	defer s.Close()
+	m := s.Wrap("model", inner)
+
+	_, _ = m.Encode(ctx, "old", 0)
+	require.NoError(t, s.Compact(0))
+	_, _ = m.Encode(ctx, "old", 0)
+	assert.Equal(t, 1, inner.encoded, "the entries are kept")
+
+	// the entries are timestamped with second precision
+	time.Sleep(1100 * time.Millisecond)
+	_, _ = m.Encode(ctx, "new", 0)
+	require.NoError(t, s.Compact(time.Second))
+	_, _ = m.EncodeBatch(ctx, []string{"old", "new"}, 0)
+	assert.Equal(t, 3, inner.encoded, "only the expired entry is encoded again")
+}
+
+func TestStore_Compact_StaleFile(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingEncoder{}
+	s, err := Open(dir, nil)
+	require.NoError(t, err)
+	defer s.Close()
+	m := s.Wrap("model", inner)
+	_, _ = m.Encode(context.Background(), "abc", 0)
+
+	// left by a compaction interrupted by a crash
+	tmpPath := filepath.Join(dir, FileName+".compact")
+	require.NoError(t, os.WriteFile(tmpPath, []byte("garbage"), 0o644))
+	require.NoError(t, s.Compact(0))
+	assert.NoFileExists(t, tmpPath)
+
+	_, _ = m.Encode(context.Background(), "abc", 0)
+	assert.Equal(t, 1, inner.encoded, "the entries are kept")
+}
+
+func TestStore_Float64(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	s, err := Open(dir, nil)
+	require.NoError(t, err)
+	_, err = s.Wrap("model", float64Encoder{}).Encode(ctx, "abc", 0)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir, &Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer s.Close()
+	inner := &countingEncoder{}
+	r, err := s.Wrap("model", inner).Encode(ctx, "abc", 0)
+	require.NoError(t, err)
+	assert.Zero(t, inner.encoded)
+	assert.Equal(t, []float64{3.1}, r.Vector.Data().F64(), "the data type is kept")
+	assert.Equal(t, 64, r.Vector.Data().BitSize())
+}
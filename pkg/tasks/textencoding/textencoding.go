@@ -29,10 +29,17 @@ const (
 // produced a sequence that exceeds the maximum allowed length.
 var ErrInputSequenceTooLong = errors.New("input sequence too long")
 
+// ErrInvalidPoolingStrategy means that the pooling strategy of the request
+// is not supported by the model.
+var ErrInvalidPoolingStrategy = errors.New("invalid pooling strategy")
+
 // Interface defines the main functions for text encoding task.
 type Interface interface {
 	// Encode returns the encoded representation of the given example.
 	Encode(ctx context.Context, text string, poolingStrategy int) (Response, error)
+	// EncodeBatch returns the encoded representation of each given example,
+	// in the same order as the input texts.
+	EncodeBatch(ctx context.Context, texts []string, poolingStrategy int) ([]Response, error)
 }
 
 // Response contains the response from text classification.
@@ -11,20 +11,26 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/embedding"
 	"github.com/yinziyang/cybertron/pkg/generationutils"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bart"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/bpetokenizer"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/sentencepiece"
-	"github.com/yinziyang/cybertron/pkg/utils/nullable"
-	"github.com/nlpodyssey/spago/mat"
-	"github.com/nlpodyssey/spago/nn"
-	"github.com/nlpodyssey/spago/nn/embedding"
 )
 
 var _ textgeneration.Interface = &TextGeneration{}
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "text-generation"
+
 // TextGeneration contains the ModelForConditionalGeneration and the Tokenizer
 // used for conditional generation tasks.
 // For example, Machine Translation and Summarization.
@@ -107,37 +113,147 @@ func doesFileExist(fileName string) bool {
 // Generate generates a text from the input.
 func (m *TextGeneration) Generate(ctx context.Context, text string, opts *textgeneration.Options) (textgeneration.Response, error) {
 	if opts == nil {
-		opts = &textgeneration.Options{
-			Temperature: nullable.Type[float64]{Value: 1.0, Valid: true},
-			Sample:      nullable.Type[bool]{Value: false, Valid: true},
-			TopK:        nullable.Type[int]{Valid: false},
-			TopP:        nullable.Type[float64]{Valid: false},
+		opts = textgeneration.DefaultOptions()
+	}
+	tokenized, err := m.tokenize(ctx, text)
+	if err != nil {
+		return textgeneration.Response{}, err
+	}
+	config, err := m.decoderConfigWithOptions(*opts)
+	if err != nil {
+		return textgeneration.Response{}, err
+	}
+	result := m.generate(ctx, tokenized, config, *opts, nil)
+	if err := ctx.Err(); err != nil {
+		// the decoding was interrupted, so the result is partial
+		return textgeneration.Response{}, err
+	}
+	return result, nil
+}
+
+// GenerateStream generates a text from the input, emitting the partial text
+// of the best hypothesis after each decoding step.
+func (m *TextGeneration) GenerateStream(ctx context.Context, text string, opts *textgeneration.Options) (<-chan textgeneration.Chunk, error) {
+	if opts == nil {
+		opts = textgeneration.DefaultOptions()
+	}
+	tokenized, err := m.tokenize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	config, err := m.decoderConfigWithOptions(*opts)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan textgeneration.Chunk)
+	send := func(c textgeneration.Chunk) {
+		select {
+		case chunks <- c:
+		case <-ctx.Done():
 		}
 	}
+
+	go func() {
+		defer close(chunks)
+
+		var lastText string
+		onStep := func(sequence []int) {
+			text, _ := cutAtStopSequence(m.Tokenizer.Detokenize(sequence, true), opts.StopSequences)
+			if text == lastText {
+				return
+			}
+			lastText = text
+			send(textgeneration.Chunk{Text: text})
+		}
+
+		result := m.generate(ctx, tokenized, config, *opts, onStep)
+		if err := ctx.Err(); err != nil {
+			send(textgeneration.Chunk{Final: true, Err: err})
+			return
+		}
+
+		final := textgeneration.Chunk{Final: true, Response: result}
+		if len(result.Texts) > 0 {
+			final.Text = result.Texts[0]
+		}
+		send(final)
+	}()
+
+	return chunks, nil
+}
+
+// tokenize returns the token IDs of the input text, ensuring they do not
+// exceed the maximum length allowed by the model.
+func (m *TextGeneration) tokenize(ctx context.Context, text string) ([]int, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	tokenized, err := m.Tokenizer.Tokenize(text)
 	if err != nil {
-		return textgeneration.Response{}, err
+		span.End()
+		return nil, err
 	}
-	if l, k := len(tokenized), m.Model.Bart.Config.MaxLength; l > k {
-		return textgeneration.Response{}, fmt.Errorf("%w: %d > %d", textgeneration.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(tokenized)))
+	instrument.InputTokens(ctx, taskName, len(tokenized))
+	if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bart.Config.MaxLength); l > k {
+		return nil, taskerrors.InputTooLong(textgeneration.ErrInputSequenceTooLong, l, k)
 	}
+	return tokenized, nil
+}
 
-	sequences, scores := m.process(ctx, tokenized, *opts)
+func (m *TextGeneration) generate(ctx context.Context, inputIDs []int, config generationutils.Config, opts textgeneration.Options, onStep generationutils.StepFunc) textgeneration.Response {
+	sequences, scores := m.process(ctx, inputIDs, config, opts, onStep)
+	if n := opts.NumReturnSequences; n.Valid && n.Value < len(sequences) {
+		sequences, scores = sequences[:n.Value], scores[:n.Value]
+	}
+
+	_, span := instrument.Start(ctx, taskName, instrument.Detokenization)
+	defer span.End()
 	result := textgeneration.Response{
 		Texts:  make([]string, len(sequences)),
 		Scores: make([]float64, len(scores)),
 	}
 	for i, sequence := range sequences {
-		result.Texts[i], result.Scores[i] = m.Tokenizer.Detokenize(sequence, true), scores[i]
+		result.Texts[i], _ = cutAtStopSequence(m.Tokenizer.Detokenize(sequence, true), opts.StopSequences)
+		result.Scores[i] = scores[i]
 	}
-	return result, nil
+	return result
 }
 
-func (m *TextGeneration) process(ctx context.Context, inputIDs []int, opts textgeneration.Options) ([][]int, []float64) {
-	next := m.Model.DecodingFunc(inputIDs, m.logProbProcessor(opts), true)
-	cache := make([]bart.Cache, m.Model.Bart.Config.NumBeams)
+// cutAtStopSequence returns the text before the first occurrence of any of
+// the stop sequences, reporting whether a stop sequence was found.
+func cutAtStopSequence(text string, stopSequences []string) (string, bool) {
+	cut := -1
+	for _, stop := range stopSequences {
+		if stop == "" {
+			continue
+		}
+		if i := strings.Index(text, stop); i >= 0 && (cut == -1 || i < cut) {
+			cut = i
+		}
+	}
+	if cut == -1 {
+		return text, false
+	}
+	return text[:cut], true
+}
+
+func (m *TextGeneration) process(ctx context.Context, inputIDs []int, config generationutils.Config, opts textgeneration.Options, onStep generationutils.StepFunc) ([][]int, []float64) {
+	ctx, forward := instrument.Start(ctx, taskName, instrument.Forward, instrument.BeamsKey.Int(config.NumBeams))
+	defer forward.End()
+
+	_, span := instrument.Start(ctx, taskName, instrument.Encoding, instrument.TokensKey.Int(len(inputIDs)))
+	next := m.Model.DecodingFunc(inputIDs, logProbProcessor(config, opts), true)
+	span.End()
+
+	cache := make([]bart.Cache, config.NumBeams)
+	step := 0
 
 	predictNext := func(decodingInputIDs [][]int, lastBeamIndices []int) []mat.Matrix {
+		_, span := instrument.Start(ctx, taskName, instrument.DecodingStep,
+			instrument.StepKey.Int(step), instrument.BeamsKey.Int(len(decodingInputIDs)))
+		defer span.End()
+		step++
+
 		cache = reorderCache(cache, lastBeamIndices)
 		batch := m.batch(decodingInputIDs, cache)
 		logProbValues := make([]mat.Matrix, len(batch))
@@ -149,9 +265,16 @@ func (m *TextGeneration) process(ctx context.Context, inputIDs []int, opts textg
 	}
 
 	decoder := &generationutils.BeamSearchDecoder{
-		Config:      decoderConfig(m.Model.Bart.Config),
+		Config:      config,
 		PredictNext: predictNext,
 		SelectNext:  decodingStrategy(opts),
+		OnStep:      onStep,
+	}
+	if len(opts.StopSequences) > 0 {
+		decoder.ShouldStop = func(bestSequence []int) bool {
+			_, found := cutAtStopSequence(m.Tokenizer.Detokenize(bestSequence, true), opts.StopSequences)
+			return found
+		}
 	}
 	return decoder.Decode(ctx)
 }
@@ -185,7 +308,7 @@ func decodingStrategy(opts textgeneration.Options) generationutils.DecodingStrat
 }
 
 // logProbProcessor returns a function that processes the log-probabilities.
-func (m *TextGeneration) logProbProcessor(opts textgeneration.Options) generationutils.ScoreProcessor {
+func logProbProcessor(config generationutils.Config, opts textgeneration.Options) generationutils.ScoreProcessor {
 	procs := make([]generationutils.ScoreProcessor, 0, 3)
 	if opts.Temperature.Valid {
 		procs = append(procs, generationutils.TemperatureProcessor(opts.Temperature.Value))
@@ -195,7 +318,7 @@ func (m *TextGeneration) logProbProcessor(opts textgeneration.Options) generatio
 	}
 	if opts.TopP.Valid {
 		minSize := 1
-		if m.Model.Bart.Config.NumBeams > 1 {
+		if config.NumBeams > 1 {
 			minSize = 2
 		}
 		procs = append(procs, generationutils.TopPProcessor(opts.TopP.Value, math.Inf(-1), minSize))
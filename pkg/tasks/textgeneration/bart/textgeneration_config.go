@@ -5,8 +5,21 @@
 package bart
 
 import (
+	"fmt"
+
 	"github.com/yinziyang/cybertron/pkg/generationutils"
 	"github.com/yinziyang/cybertron/pkg/models/bart"
+	"github.com/yinziyang/cybertron/pkg/tasks/textgeneration"
+)
+
+const (
+	// maxNumBeams is the maximum number of beams of a request, unless the
+	// model defaults to more: each beam holds its own decoder cache and
+	// hypotheses.
+	maxNumBeams = 16
+	// maxLength is the maximum length of a request for the models with no
+	// limit of position embeddings, unless the model defaults to more.
+	maxLength = 1024
 )
 
 // decoderConfig converts the Bart model Config to a generationutils.Config.
@@ -27,3 +40,108 @@ func decoderConfig(c bart.Config) generationutils.Config {
 		NoRepeatNGramSize:   c.NoRepeatNGramSize,
 	}
 }
+
+// decoderConfigWithOptions returns the decoder configuration of the model,
+// overridden by the per-request options.
+func (m *TextGeneration) decoderConfigWithOptions(opts textgeneration.Options) (generationutils.Config, error) {
+	modelConfig := m.Model.Bart.Config
+	c := decoderConfig(modelConfig)
+
+	if opts.NumBeams.Valid {
+		c.NumBeams = opts.NumBeams.Value
+	}
+	if opts.MinLength.Valid {
+		c.MinLength = opts.MinLength.Value
+	}
+	if opts.MaxLength.Valid {
+		c.MaxLength = opts.MaxLength.Value
+	}
+	if opts.MaxNewTokens.Valid {
+		if opts.MaxNewTokens.Value < 1 {
+			return generationutils.Config{}, fmt.Errorf("%w: max new tokens must be positive", textgeneration.ErrInvalidOptions)
+		}
+		// the decoder start token counts towards the sequence length
+		c.MaxLength = opts.MaxNewTokens.Value + 1
+	}
+	if opts.LengthPenalty.Valid {
+		c.LengthPenalty = opts.LengthPenalty.Value
+	}
+	if opts.EarlyStopping.Valid {
+		c.EarlyStopping = opts.EarlyStopping.Value
+	}
+	if opts.NoRepeatNGramSize.Valid {
+		c.NoRepeatNGramSize = opts.NoRepeatNGramSize.Value
+	}
+	if len(opts.BadWords) > 0 {
+		ids, err := m.badWordsIDs(opts.BadWords)
+		if err != nil {
+			return generationutils.Config{}, err
+		}
+		c.BadWordsIDs = append(append(make([][]int, 0, len(c.BadWordsIDs)+len(ids)), c.BadWordsIDs...), ids...)
+	}
+
+	maxBeams := max(maxNumBeams, modelConfig.NumBeams)
+	maxLen := modelConfig.MaxPositionEmbeddings
+	if maxLen <= 0 {
+		maxLen = max(maxLength, modelConfig.MaxLength)
+	}
+
+	switch {
+	case c.NumBeams < 1:
+		return generationutils.Config{}, fmt.Errorf("%w: num beams must be positive", textgeneration.ErrInvalidOptions)
+	case c.NumBeams > maxBeams:
+		return generationutils.Config{}, fmt.Errorf("%w: num beams %d exceeds the limit %d", textgeneration.ErrInvalidOptions, c.NumBeams, maxBeams)
+	case c.MaxLength < 2:
+		return generationutils.Config{}, fmt.Errorf("%w: max length must be greater than 1", textgeneration.ErrInvalidOptions)
+	case c.MaxLength > maxLen:
+		return generationutils.Config{}, fmt.Errorf("%w: max length %d exceeds the limit %d", textgeneration.ErrInvalidOptions, c.MaxLength, maxLen)
+	case c.MinLength < 0 || c.MinLength > c.MaxLength:
+		return generationutils.Config{}, fmt.Errorf("%w: min length must be between 0 and max length", textgeneration.ErrInvalidOptions)
+	case c.NoRepeatNGramSize < 0:
+		return generationutils.Config{}, fmt.Errorf("%w: no-repeat n-gram size must not be negative", textgeneration.ErrInvalidOptions)
+	case opts.NumReturnSequences.Valid && (opts.NumReturnSequences.Value < 1 || opts.NumReturnSequences.Value > c.NumBeams):
+		return generationutils.Config{}, fmt.Errorf("%w: num return sequences must be between 1 and num beams (%d)", textgeneration.ErrInvalidOptions, c.NumBeams)
+	}
+	return c, nil
+}
+
+// badWordsIDs tokenizes the given words, stripping the special tokens.
+// Each word is tokenized both as is and with a leading space, since
+// tokenizers usually encode words differently at the beginning of a text.
+func (m *TextGeneration) badWordsIDs(words []string) ([][]int, error) {
+	seen := make(map[string]struct{}, len(words)*2)
+	result := make([][]int, 0, len(words)*2)
+	for _, word := range words {
+		for _, variant := range []string{word, " " + word} {
+			tokenized, err := m.Tokenizer.Tokenize(variant)
+			if err != nil {
+				return nil, fmt.Errorf("failed to tokenize bad word %q: %w", word, err)
+			}
+			ids := m.stripSpecialTokens(tokenized)
+			if len(ids) == 0 {
+				continue
+			}
+			key := fmt.Sprint(ids)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			result = append(result, ids)
+		}
+	}
+	return result, nil
+}
+
+// stripSpecialTokens returns the token IDs without the special tokens
+// added by the tokenizer.
+func (m *TextGeneration) stripSpecialTokens(ids []int) []int {
+	c := m.Model.Bart.Config
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == c.EosTokenID || id == c.BosTokenID || id == c.PadTokenID || id == c.DecoderStartTokenID {
+			continue
+		}
+		result = append(result, id)
+	}
+	return result
+}
@@ -55,6 +55,10 @@ func DefaultModelForMachineTranslation(source, target string) string {
 type Interface interface {
 	// Generate generates text (e.g. translation, summarization, paraphrase) from the given input.
 	Generate(ctx context.Context, text string, opts *Options) (Response, error)
+	// GenerateStream works like Generate, but it emits the partial text as
+	// the best hypothesis grows. The returned channel is closed after the
+	// final chunk has been sent, or when the context is done.
+	GenerateStream(ctx context.Context, text string, opts *Options) (<-chan Chunk, error)
 }
 
 // Options defines the options for generating text.
@@ -67,6 +71,33 @@ type Options struct {
 	TopK nullable.Type[int]
 	// TopP is the top-p candidates to be considered during generation.
 	TopP nullable.Type[float64]
+	// NumBeams is the number of beams for the decoding search.
+	NumBeams nullable.Type[int]
+	// MinLength is the minimum length of the sequence to be generated.
+	MinLength nullable.Type[int]
+	// MaxLength is the maximum length of the sequence to be generated.
+	MaxLength nullable.Type[int]
+	// MaxNewTokens is the maximum number of tokens to generate, ignoring
+	// the decoder start token. It takes precedence over MaxLength.
+	MaxNewTokens nullable.Type[int]
+	// LengthPenalty is the exponential penalty to the length.
+	LengthPenalty nullable.Type[float64]
+	// EarlyStopping reports whether to stop the decoding search as soon as
+	// NumBeams sentences are finished.
+	EarlyStopping nullable.Type[bool]
+	// NoRepeatNGramSize, when positive, prevents n-grams of this size from
+	// occurring more than once.
+	NoRepeatNGramSize nullable.Type[int]
+	// NumReturnSequences is the number of generated sequences to return.
+	// It must not exceed the number of beams. All beams are returned if unset.
+	NumReturnSequences nullable.Type[int]
+	// StopSequences is a list of strings that end the generation as soon as
+	// the best hypothesis contains one of them. Generated texts are
+	// truncated before the first stop sequence.
+	StopSequences []string
+	// BadWords is a list of words that are not allowed to be generated.
+	// They are tokenized with the model's tokenizer.
+	BadWords []string
 }
 
 // Response contains the result of the text generation.
@@ -77,10 +108,30 @@ type Response struct {
 	Scores []float64
 }
 
+// Chunk is a partial result of a streamed text generation.
+type Chunk struct {
+	// Text is the text of the best hypothesis generated so far.
+	// Since the best hypothesis can change from one decoding step to the
+	// next, each chunk supersedes the previous one rather than extending it.
+	Text string
+	// Final reports whether this is the last chunk of the stream.
+	Final bool
+	// Response contains the complete result of the generation.
+	// It is set only on the final chunk.
+	Response Response
+	// Err is set if the generation failed. A chunk carrying an error
+	// is always final.
+	Err error
+}
+
 // ErrInputSequenceTooLong means that pre-processing the input text
 // produced a sequence that exceeds the maximum allowed length.
 var ErrInputSequenceTooLong = errors.New("input sequence too long")
 
+// ErrInvalidOptions means that the options for generating text are not
+// valid, or not compatible with each other or with the model.
+var ErrInvalidOptions = errors.New("invalid options")
+
 // DefaultOptions returns the default options for generating text.
 func DefaultOptions() *Options {
 	return &Options{
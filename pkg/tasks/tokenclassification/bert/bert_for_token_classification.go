@@ -7,9 +7,9 @@ package bert
 import (
 	"strings"
 
+	"github.com/nlpodyssey/spago/mat"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
-	"github.com/nlpodyssey/spago/mat"
 )
 
 type ModelForTokenClassification struct {
@@ -21,9 +21,22 @@ func (m *ModelForTokenClassification) Classify(tokens []string) []mat.Tensor {
 	return m.Classifier.Forward(m.EncodeAndReduce(tokens)...)
 }
 
+// ClassifyBatch returns the logits for each token of each sequence in the batch.
+func (m *ModelForTokenClassification) ClassifyBatch(batch [][]string) [][]mat.Tensor {
+	encoded := m.Bert.EncodeTokensBatch(batch)
+	result := make([][]mat.Tensor, len(encoded))
+	for i, tokens := range batch {
+		result[i] = m.Classifier.Forward(reduce(tokens, encoded[i])...)
+	}
+	return result
+}
+
 func (m *ModelForTokenClassification) EncodeAndReduce(tokens []string) []mat.Tensor {
-	encoded := m.Bert.EncodeTokens(tokens)
+	return reduce(tokens, m.Bert.EncodeTokens(tokens))
+}
 
+// reduce discards the encoded states of the special tokens and sub-words.
+func reduce(tokens []string, encoded []mat.Tensor) []mat.Tensor {
 	result := make([]mat.Tensor, 0, len(tokens))
 	for i, token := range tokens {
 		if isSpecialToken(token) {
@@ -12,16 +12,22 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/rs/zerolog/log"
+	"github.com/yinziyang/cybertron/pkg/instrument"
 	"github.com/yinziyang/cybertron/pkg/models/bert"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
 	"github.com/yinziyang/cybertron/pkg/tasks/tokenclassification"
 	"github.com/yinziyang/cybertron/pkg/tokenizers"
 	"github.com/yinziyang/cybertron/pkg/tokenizers/wordpiecetokenizer"
 	"github.com/yinziyang/cybertron/pkg/vocabulary"
-	"github.com/nlpodyssey/spago/mat"
-	"github.com/nlpodyssey/spago/nn"
-	"github.com/rs/zerolog/log"
 )
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "token-classification"
+
 // TokenClassification is a token classification model.
 type TokenClassification struct {
 	// Model is the model used to answer questions.
@@ -82,13 +88,50 @@ func ID2Label(value map[string]string) []string {
 }
 
 // Classify returns the classification of the given text.
-func (m *TokenClassification) Classify(_ context.Context, text string, parameters tokenclassification.Parameters) (tokenclassification.Response, error) {
+func (m *TokenClassification) Classify(ctx context.Context, text string, parameters tokenclassification.Parameters) (tokenclassification.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	tokenized := m.tokenize(text)
-	if l, k := len(tokenized), m.Model.Bert.Config.MaxPositionEmbeddings; l > k {
-		return tokenclassification.Response{}, fmt.Errorf("%w: %d > %d", tokenclassification.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(tokenized)))
+	instrument.InputTokens(ctx, taskName, len(tokenized))
+	if l, k := len(tokenized), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+		return tokenclassification.Response{}, taskerrors.InputTooLong(tokenclassification.ErrInputSequenceTooLong, l, k)
 	}
 
+	_, span = instrument.Start(ctx, taskName, instrument.Forward)
 	logits := m.Model.Classify(pad(tokenizers.GetStrings(tokenized)))
+	span.End()
+	return m.response(text, tokenized, logits, parameters), nil
+}
+
+// ClassifyBatch returns the classification of each given text.
+// The texts are padded to the same length and classified in a single forward pass.
+func (m *TokenClassification) ClassifyBatch(ctx context.Context, texts []string, parameters tokenclassification.Parameters) ([]tokenclassification.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization, instrument.BatchSizeKey.Int(len(texts)))
+	tokenized := make([][]tokenizers.StringOffsetsPair, len(texts))
+	batch := make([][]string, len(texts))
+	for i, text := range texts {
+		tokenized[i] = m.tokenize(text)
+		instrument.BatchInputTokens(ctx, taskName, i, len(tokenized[i]))
+		if l, k := len(tokenized[i]), inputlimit.MaxTokens(ctx, m.Model.Bert.Config.MaxPositionEmbeddings); l > k {
+			span.End()
+			return nil, taskerrors.InputTooLongAt(tokenclassification.ErrInputSequenceTooLong, i, l, k)
+		}
+		batch[i] = pad(tokenizers.GetStrings(tokenized[i]))
+	}
+	span.End()
+
+	_, span = instrument.Start(ctx, taskName, instrument.Forward, instrument.BatchSizeKey.Int(len(texts)))
+	logits := m.Model.ClassifyBatch(batch)
+	span.End()
+	responses := make([]tokenclassification.Response, len(texts))
+	for i, text := range texts {
+		responses[i] = m.response(text, tokenized[i], logits[i], parameters)
+	}
+	return responses, nil
+}
+
+// response builds the response labeling each word of the text with its best class.
+func (m *TokenClassification) response(text string, tokenized []tokenizers.StringOffsetsPair, logits []mat.Tensor, parameters tokenclassification.Parameters) tokenclassification.Response {
 	tokens := make([]tokenclassification.Token, 0, len(tokenized))
 	for i, token := range wordpiecetokenizer.GroupSubWords(tokenized) {
 		label, score := m.getBestClass(logits[i])
@@ -106,10 +149,9 @@ func (m *TokenClassification) Classify(_ context.Context, text string, parameter
 		tokens = tokenclassification.FilterNotEntities(tokenclassification.Aggregate(tokens))
 	}
 
-	response := tokenclassification.Response{
+	return tokenclassification.Response{
 		Tokens: tokens,
 	}
-	return response, nil
 }
 
 func (m *TokenClassification) getBestClass(logits mat.Tensor) (label string, score float64) {
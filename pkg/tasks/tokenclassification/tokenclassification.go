@@ -49,6 +49,9 @@ type Parameters struct {
 type Interface interface {
 	// Classify returns the classification of the given example.
 	Classify(ctx context.Context, text string, parameters Parameters) (Response, error)
+	// ClassifyBatch returns the classification of each given example,
+	// in the same order as the input texts.
+	ClassifyBatch(ctx context.Context, texts []string, parameters Parameters) ([]Response, error)
 }
 
 // Token is a labeled text token.
@@ -12,14 +12,17 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/yinziyang/cybertron/pkg/models/bart"
-	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
-	"github.com/yinziyang/cybertron/pkg/tokenizers/bpetokenizer"
-	"github.com/yinziyang/cybertron/pkg/utils/sliceutils"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn"
 	"github.com/nlpodyssey/spago/nn/embedding"
+	"github.com/yinziyang/cybertron/pkg/instrument"
+	"github.com/yinziyang/cybertron/pkg/models/bart"
+	"github.com/yinziyang/cybertron/pkg/tasks/inputlimit"
+	"github.com/yinziyang/cybertron/pkg/tasks/taskerrors"
+	"github.com/yinziyang/cybertron/pkg/tasks/zeroshotclassifier"
+	"github.com/yinziyang/cybertron/pkg/tokenizers/bpetokenizer"
+	"github.com/yinziyang/cybertron/pkg/utils/sliceutils"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -30,6 +33,9 @@ const (
 	defaultEndTokenID   = 2
 )
 
+// taskName is the name of the task reported to the instrument.Observer.
+const taskName = "zero-shot-classification"
+
 // ZeroShotClassifier contains the ModelForSequenceClassification and the Tokenizer
 // used for zero-shot classification tasks.
 type ZeroShotClassifier struct {
@@ -73,13 +79,17 @@ func LoadZeroShotClassifier(modelPath string) (*ZeroShotClassifier, error) {
 }
 
 // Classify classifies the input.
-func (m *ZeroShotClassifier) Classify(_ context.Context, text string, parameters zeroshotclassifier.Parameters) (zeroshotclassifier.Response, error) {
+func (m *ZeroShotClassifier) Classify(ctx context.Context, text string, parameters zeroshotclassifier.Parameters) (zeroshotclassifier.Response, error) {
+	_, span := instrument.Start(ctx, taskName, instrument.Tokenization)
 	premise, err := m.tokenize(text, defaultStartTokenID, defaultEndTokenID)
 	if err != nil {
+		span.End()
 		return zeroshotclassifier.Response{}, err
 	}
-	if l, k := len(premise), m.Model.Bart.Config.MaxLength; l > k {
-		return zeroshotclassifier.Response{}, fmt.Errorf("%w: %d > %d", zeroshotclassifier.ErrInputSequenceTooLong, l, k)
+	span.End(instrument.TokensKey.Int(len(premise)))
+	instrument.InputTokens(ctx, taskName, len(premise))
+	if l, k := len(premise), inputlimit.MaxTokens(ctx, m.Model.Bart.Config.MaxLength); l > k {
+		return zeroshotclassifier.Response{}, taskerrors.InputTooLong(zeroshotclassifier.ErrInputSequenceTooLong, l, k)
 	}
 
 	// If the API request does not specify a HypothesisTemplate, then use the default
@@ -91,6 +101,7 @@ func (m *ZeroShotClassifier) Classify(_ context.Context, text string, parameters
 	multiClass := parameters.MultiLabel || len(parameters.CandidateLabels) == 1
 	scoreFn := m.score(premise, multiClass)
 
+	_, span = instrument.Start(ctx, taskName, instrument.Forward, instrument.BatchSizeKey.Int(len(parameters.CandidateLabels)))
 	ch := make(chan struct{}, runtime.NumCPU())
 	eg, _ := errgroup.WithContext(context.Background())
 
@@ -113,7 +124,9 @@ func (m *ZeroShotClassifier) Classify(_ context.Context, text string, parameters
 			return err
 		})
 	}
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	span.End()
+	if err != nil {
 		return zeroshotclassifier.Response{}, err
 	}
 	for i := 0; i < len(ch); i++ {